@@ -0,0 +1,86 @@
+package introspection
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FailureClass decides how the backoff system reacts to a failed sync.
+type FailureClass string
+
+const (
+	FailureRetryable FailureClass = "retryable" // Network errors, 5xx, 408: retry with the backoff schedule
+	FailureThrottled FailureClass = "throttled" // 429, 503: wait Retry-After (backoff schedule without one)
+	FailurePermanent FailureClass = "permanent" // 400, 401, 403, other 4xx: retrying will not help
+)
+
+// SyncError is a non-2xx response from introspection.
+type SyncError struct {
+	Phase      string        // "roots", "checksums" or "components"
+	StatusCode int           // HTTP status
+	Body       string        // Response body (error message from introspection)
+	RetryAfter time.Duration // Retry-After header (0 = none)
+}
+
+// Error keeps the message format of the sync logs ("HTTP 503: ...").
+func (e *SyncError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// Class classifies the response status.
+func (e *SyncError) Class() FailureClass {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusServiceUnavailable:
+		return FailureThrottled
+	case e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooEarly:
+		return FailureRetryable
+	case e.StatusCode >= 400 && e.StatusCode < 500:
+		return FailurePermanent
+	default:
+		return FailureRetryable
+	}
+}
+
+// newSyncError creates a SyncError from a response whose body was already read.
+func newSyncError(phase string, resp *http.Response, body []byte) *SyncError {
+	return &SyncError{
+		Phase:      phase,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// classifyFailure returns the failure class and server-requested delay of a sync error.
+// Errors without an HTTP response (network, timeouts, decoding) are retryable.
+func classifyFailure(err error) (FailureClass, time.Duration) {
+	var syncErr *SyncError
+	if errors.As(err, &syncErr) {
+		return syncErr.Class(), syncErr.RetryAfter
+	}
+	return FailureRetryable, 0
+}
+
+// parseRetryAfter parses delay-seconds or an HTTP-date (RFC 9110). Returns 0 if absent or invalid,
+// capped at maxRetryAfter.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = date.Sub(now)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return min(delay, maxRetryAfter)
+}
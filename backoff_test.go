@@ -0,0 +1,70 @@
+package introspection
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "absent", value: "", want: 0},
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "seconds with spaces", value: "  30 ", want: 30 * time.Second},
+		{name: "zero", value: "0", want: 0},
+		{name: "negative", value: "-5", want: 0},
+		{name: "invalid", value: "soon", want: 0},
+		{name: "fractional", value: "1.5", want: 0},
+		{name: "http-date future", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{name: "http-date past", value: now.Add(-time.Hour).Format(http.TimeFormat), want: 0},
+		{name: "http-date rfc850", value: "Sunday, 01-Mar-26 12:05:00 GMT", want: 5 * time.Minute},
+		{name: "seconds capped", value: "86400", want: maxRetryAfter},
+		{name: "http-date capped", value: now.Add(24 * time.Hour).Format(http.TimeFormat), want: maxRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantClass FailureClass
+		wantDelay time.Duration
+	}{
+		{name: "network error", err: errors.New("dial tcp: connection refused"), wantClass: FailureRetryable},
+		{name: "429 with Retry-After", err: &SyncError{StatusCode: 429, RetryAfter: 30 * time.Second}, wantClass: FailureThrottled, wantDelay: 30 * time.Second},
+		{name: "503 without Retry-After", err: &SyncError{StatusCode: 503}, wantClass: FailureThrottled},
+		{name: "500", err: &SyncError{StatusCode: 500}, wantClass: FailureRetryable},
+		{name: "502", err: &SyncError{StatusCode: 502}, wantClass: FailureRetryable},
+		{name: "408", err: &SyncError{StatusCode: 408}, wantClass: FailureRetryable},
+		{name: "425", err: &SyncError{StatusCode: 425}, wantClass: FailureRetryable},
+		{name: "400", err: &SyncError{StatusCode: 400}, wantClass: FailurePermanent},
+		{name: "401", err: &SyncError{StatusCode: 401}, wantClass: FailurePermanent},
+		{name: "404", err: &SyncError{StatusCode: 404}, wantClass: FailurePermanent},
+		{name: "wrapped", err: fmt.Errorf("phase 2: %w", &SyncError{StatusCode: 429, RetryAfter: time.Minute}), wantClass: FailureThrottled, wantDelay: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, delay := classifyFailure(tt.err)
+			if class != tt.wantClass || delay != tt.wantDelay {
+				t.Errorf("classifyFailure = (%s, %v), want (%s, %v)", class, delay, tt.wantClass, tt.wantDelay)
+			}
+		})
+	}
+}
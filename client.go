@@ -9,16 +9,20 @@
 // Architecture: Services provide data, library handles ALL protocol complexity.
 // Standard Components: Automatically registered (service-info, logs, connectivity, certificates).
 //
-// Version: 2.5.0 (complete rewrite based on ADR-032)
+// Version: see LibraryVersion (2.x: complete rewrite based on ADR-032)
 package introspection
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -36,7 +40,7 @@ const HeartbeatIntervalSec = 59
 // Config holds client configuration (NO DEFAULTS - all required!).
 type Config struct {
 	ServiceName      string // Service name (e.g., "ca-manager")
-	Version          string // Service version (e.g., "1.0.0")
+	Version          string // Service version (e.g., "1.0.0"; empty = module version or VCS revision from build info)
 	Port             int    // Service port (e.g., 8443)
 	Server           string // Server name: "staging" or "production"
 	IntrospectionURL string // Introspection service URL (e.g., "https://introspection:9080")
@@ -44,15 +48,57 @@ type Config struct {
 	KeyPath          string // Path to client key
 	CAPath           string // Path to CA certificate
 	CertDir          string // Directory containing *.cert.pem files for monitoring
+
+	// Optional: dependencies to probe actively (empty = no probing)
+	Probes []standard.ProbeTarget
+
+	// Optional: days before expiry that trigger a certificates update (empty = 30/14/7/1)
+	CertExpiryThresholds []int
+
+	// Optional: certificate discovery (roots, recursive patterns, DER/PKCS#12, purpose rules).
+	// Zero value = CertDir/*.cert.pem with filename-based purpose.
+	CertDiscovery standard.CertificateDiscovery
+
+	// Optional: OCSP/CRL revocation checks of monitored certificates (zero value = disabled).
+	// OCSPURL/CRLURL override the endpoints in the certificates (e.g., a local test responder).
+	CertRevocation standard.RevocationConfig
+
+	// Optional: update interval of the runtime component (goroutines, heap, GC, RSS, fds; 0 = update.Slow)
+	RuntimeStatsInterval update.Interval
+
+	// Optional: sync payload compression (transport.EncodingGzip/EncodingZstd, empty = uncompressed).
+	// Falls back to the next encoding when introspection answers 415 Unsupported Media Type.
+	Compression          transport.Encoding
+	CompressionThreshold int // Bytes below which payloads stay uncompressed (0 = transport.DefaultCompressionThreshold)
+
+	// Optional: send changed components as RFC 6902 JSON Patch against the data introspection
+	// acknowledged last (when smaller than the full data). Used only if the server advertises FeatureDelta.
+	DeltaEncoding bool
+
+	// Optional: send one root hash per entity (+ overall) first and expand to per-component
+	// checksums only for entities whose root differs. Used only if the server advertises FeatureRootHash.
+	RootHashSync bool
+
+	// Optional: delays between retries of a failed sync (empty = 1,2,3,5,11,23,47,61s).
+	// The last delay repeats; all are capped at the heartbeat interval.
+	BackoffSchedule []time.Duration
+
+	// Optional: dial/TLS handshake/response header timeouts (zero fields = transport defaults)
+	// and the deadline of each sync request (hello, roots, checksums, components; 0 = DefaultPhaseTimeout).
+	Timeouts     transport.Timeouts
+	PhaseTimeout time.Duration
 }
 
+// DefaultPhaseTimeout is the deadline of a single sync request (Config.PhaseTimeout).
+const DefaultPhaseTimeout = 30 * time.Second
+
 // Validate checks if all required config fields are present.
 func (c Config) Validate() error {
 	if c.ServiceName == "" {
 		return fmt.Errorf("ServiceName required")
 	}
-	if c.Version == "" {
-		return fmt.Errorf("Version required")
+	if c.Version == "" && standard.BuildVersion() == "" {
+		return fmt.Errorf("Version required (no module version or VCS revision in build info)")
 	}
 	if c.Port <= 0 {
 		return fmt.Errorf("Port required (must be > 0)")
@@ -75,6 +121,44 @@ func (c Config) Validate() error {
 	if c.CertDir == "" {
 		return fmt.Errorf("CertDir required")
 	}
+	if err := c.CertDiscovery.Validate(); err != nil {
+		return fmt.Errorf("invalid CertDiscovery: %w", err)
+	}
+	if err := c.CertRevocation.Validate(); err != nil {
+		return fmt.Errorf("invalid CertRevocation: %w", err)
+	}
+	switch c.RuntimeStatsInterval {
+	case 0, update.Fast, update.Medium, update.Slow:
+	default:
+		return fmt.Errorf("RuntimeStatsInterval must be update.Fast/Medium/Slow (got %d)", c.RuntimeStatsInterval)
+	}
+	if err := c.Compression.Validate(); err != nil {
+		return fmt.Errorf("invalid Compression: %w", err)
+	}
+	if c.CompressionThreshold < 0 {
+		return fmt.Errorf("CompressionThreshold must be >= 0")
+	}
+	if err := c.Timeouts.Validate(); err != nil {
+		return fmt.Errorf("invalid Timeouts: %w", err)
+	}
+	if c.PhaseTimeout < 0 {
+		return fmt.Errorf("PhaseTimeout must be >= 0")
+	}
+	for _, delay := range c.BackoffSchedule {
+		if delay <= 0 {
+			return fmt.Errorf("BackoffSchedule delays must be > 0 (got %s)", delay)
+		}
+	}
+	for _, days := range c.CertExpiryThresholds {
+		if days <= 0 {
+			return fmt.Errorf("CertExpiryThresholds must be > 0 (got %d)", days)
+		}
+	}
+	for _, probe := range c.Probes {
+		if err := probe.Validate(); err != nil {
+			return fmt.Errorf("invalid probe %q: %w", probe.Service, err)
+		}
+	}
 	return nil
 }
 
@@ -85,29 +169,50 @@ type Client struct {
 	registry *registry.Registry
 	http     *http.Client
 
+	// Sync payload compression (negotiated: downgrades on 415)
+	compressor *transport.Compressor
+
 	// Standard components (auto-registered, public access via getters)
 	logs         *standard.RecentLogs
 	connectivity *standard.ConnectivityTracker
+	prober       *standard.ConnectivityProber
 	certMonitor  *standard.CertificateMonitor
 
 	// System state
 	mu       sync.Mutex
 	running  bool
 	stopChan chan struct{}
+	ctx      context.Context    // Cancelled by Stop (aborts in-flight sync requests), recreated by Start
+	cancel   context.CancelFunc
 
 	// Heartbeat System state
-	idleSince      time.Time // Last real activity (non-heartbeat sync)
-	heartbeatTimer *time.Timer
+	idleSince         time.Time // Last real activity (non-heartbeat sync)
+	heartbeatTimer    *time.Timer
+	heartbeatInterval time.Duration // Requested by introspection (0 = HeartbeatIntervalSec)
 
 	// Update System state
 	updateTimer *time.Timer
 
 	// Backoff System state
-	backoffIndex int // Current position in prime sequence
+	backoffIndex int         // Current position in prime sequence
+	retryAfter   time.Time   // No sync before this time (server retry_after)
+	retryTimer   *time.Timer // Runs the deferred sync when retryAfter has passed
+
+	// Last permanent failure (e.g., 401/403) - nil once a sync succeeds
+	permanentFailure error
 
 	// Sync System state
 	syncMu      sync.Mutex // Protects sync execution (only one sync at a time)
 	syncPending bool       // True if sync needs to run after current sync completes
+
+	// Root hash mode disabled after the server answered 404 on /sync/roots
+	rootHashUnsupported bool
+
+	// Handshake result (see handshake.go) - nil until the first successful handshake
+	capabilities *ServerCapabilities
+
+	// Sync statistics (see status.go)
+	stats syncStats
 }
 
 // New creates a new introspection client with auto-registered standard components.
@@ -116,32 +221,67 @@ func New(config Config) (*Client, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	// Version from build info if not set explicitly (Validate ensures one exists)
+	if config.Version == "" {
+		config.Version = standard.BuildVersion()
+	}
+
 	// Build entity ID
 	entityID := fmt.Sprintf("%s-%s", config.ServiceName, config.Server)
 
 	// Create registry
 	reg := registry.New(entityID)
 
-	// Create HTTP/2 client with mTLS 1.3
-	httpClient, err := transport.BuildHTTP2Client(config.CertPath, config.KeyPath, config.CAPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build HTTP client: %w", err)
-	}
-
 	// Create standard components
 	logs := standard.NewRecentLogs(100)
 	connectivity := standard.NewConnectivityTracker()
+	prober := standard.NewConnectivityProber(connectivity)
+	for _, probe := range config.Probes {
+		if err := prober.AddTarget(probe); err != nil {
+			return nil, err
+		}
+	}
 	certMonitor := standard.NewCertificateMonitor(config.CertDir)
+	certMonitor.SetCAPath(config.CAPath) // Verify chains against the CA we trust
+	certMonitor.SetLogs(logs)            // Warn when a valid certificate becomes unparsable
+	if err := certMonitor.SetDiscovery(config.CertDiscovery); err != nil {
+		return nil, fmt.Errorf("invalid CertDiscovery: %w", err)
+	}
+	if err := certMonitor.SetRevocation(config.CertRevocation); err != nil {
+		return nil, fmt.Errorf("invalid CertRevocation: %w", err)
+	}
+	if len(config.CertExpiryThresholds) > 0 {
+		certMonitor.SetExpiryThresholds(config.CertExpiryThresholds)
+	}
 
+	// Create HTTP/2 client with mTLS 1.3 (certMonitor observes loaded + peer certificates)
+	httpClient, err := transport.BuildHTTP2ClientWithOptions(config.CertPath, config.KeyPath, config.CAPath, transport.ClientOptions{
+		Observer: certMonitor,
+		Timeouts: config.Timeouts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build HTTP client: %w", err)
+	}
+
+	compressor, err := transport.NewCompressor(config.Compression, config.CompressionThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Compression: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	client := &Client{
 		config:       config,
 		entityID:     entityID,
 		registry:     reg,
 		http:         httpClient,
+		compressor:   compressor,
 		logs:         logs,
 		connectivity: connectivity,
+		prober:       prober,
 		certMonitor:  certMonitor,
 		stopChan:     make(chan struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
 		idleSince:    time.Now(), // Service just started = activity!
 		backoffIndex: 0,
 	}
@@ -153,16 +293,24 @@ func New(config Config) (*Client, error) {
 
 	// Initial logs go to stdout only (logs not initialized yet)
 	log.Printf("✅ Introspection client initialized (entity: %s, service: %s v%s)", entityID, client.config.ServiceName, client.config.Version)
-	log.Printf("   📦 Auto-registered: service-info (static), recent-logs (59s), connectivity (59s), certificates (trigger)")
+	log.Printf("   📦 Auto-registered: service-info (59s), runtime (%ds), recent-logs (59s), connectivity (59s), certificates (trigger: file change, expiry thresholds, revocation), introspection-client (59s)", client.runtimeStatsInterval().Seconds())
 
 	return client, nil
 }
 
 // registerStandardComponents auto-registers all standard components.
 func (c *Client) registerStandardComponents() error {
-	// 1. service-info (OnlyTrigger - static data)
+	// 1. service-info (Slow = 59s) - mostly static, but network (listening ports, addresses) is
+	// read per collection; the checksum only changes when it does
 	serviceInfo := standard.AutoDetect(c.config.ServiceName, c.config.Version, c.config.Port)
-	if err := c.registry.Register("service-info", serviceInfo.GetData); err != nil {
+	if err := c.registry.Register("service-info", serviceInfo.GetData, update.Slow); err != nil {
+		return err
+	}
+
+	// 1b. runtime (configurable, default Slow = 59s) - sampled at most once per interval
+	runtimeInterval := c.runtimeStatsInterval()
+	runtimeStats := standard.NewRuntimeStats(serviceInfo.StartTime, runtimeInterval)
+	if err := c.registry.Register("runtime", runtimeStats.GetData, runtimeInterval); err != nil {
 		return err
 	}
 
@@ -182,22 +330,40 @@ func (c *Client) registerStandardComponents() error {
 		return err
 	}
 
-	// 4. certificates (OnlyTrigger - scan on demand when certs change)
-	if err := c.registry.Register("certificates", func() interface{} {
-		// Scan filesystem on every collection
-		if err := c.certMonitor.Scan(); err != nil {
-			c.logs.Warn("Certificate scan failed", map[string]interface{}{
-				"error": err.Error(),
-			})
-		}
-		return c.certMonitor.GetData()
-	}); err != nil { // No update interval - OnlyTrigger
+	// Subscribe to connection status transitions (immediate sync, including probe results)
+	c.connectivity.Subscribe(func(change standard.ConnectivityStateChange) {
+		// Non-blocking trigger
+		go c.onConnectivityStateChange(change)
+	})
+
+	// 4. certificates (OnlyTrigger - the monitor rescans on file changes, thresholds and hourly)
+	// No scan per collection: last_scan would change the checksum on every sync
+	if err := c.registry.Register("certificates", c.certMonitor.GetData); err != nil { // No update interval - OnlyTrigger
+		return err
+	}
+
+	// Trigger on file changes in CertDir and expiry-threshold crossings
+	c.certMonitor.SetTriggerFunc(func() {
+		// Non-blocking trigger
+		go c.triggerCertificateUpdate()
+	})
+
+	// 5. introspection-client (Slow = 59s) - library, protocol and sync status (see Status)
+	if err := c.registry.Register("introspection-client", c.clientComponentData, statusInterval); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// runtimeStatsInterval returns the configured runtime component interval (default update.Slow).
+func (c *Client) runtimeStatsInterval() update.Interval {
+	if c.config.RuntimeStatsInterval == 0 {
+		return update.Slow
+	}
+	return c.config.RuntimeStatsInterval
+}
+
 // triggerSyncFromLogs triggers sync when Error/Warn logged (called from logs component).
 func (c *Client) triggerSyncFromLogs() {
 	// This is REAL ACTIVITY → reset idle_since + heartbeat timer
@@ -210,6 +376,47 @@ func (c *Client) triggerSyncFromLogs() {
 	c.triggerSync("logs:error-or-warn")
 }
 
+// onConnectivityStateChange logs a connection status transition and triggers sync.
+func (c *Client) onConnectivityStateChange(change standard.ConnectivityStateChange) {
+	context := map[string]interface{}{
+		"service":    change.Service,
+		"url":        change.URL,
+		"old_status": change.OldStatus,
+		"new_status": change.NewStatus,
+	}
+	if change.LastError != "" {
+		context["last_error"] = change.LastError
+	}
+
+	// Introspection itself is tracked by the sync system - triggering sync from its
+	// own failures would create a feedback loop (sync fails → status change → sync → ...)
+	if change.Service == "introspection" {
+		c.logs.Info("Introspection connectivity changed", context)
+		return
+	}
+
+	if change.NewStatus == standard.StatusHealthy {
+		c.logs.Info("Connectivity recovered", context)
+	} else {
+		c.logs.WarnNoTrigger("Connectivity status changed", context)
+	}
+
+	if err := c.TriggerUpdate("inter-service-connectivity"); err != nil {
+		c.logs.WarnNoTrigger("Failed to trigger connectivity update", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// triggerCertificateUpdate rescans certificates and syncs (file change or threshold crossed).
+func (c *Client) triggerCertificateUpdate() {
+	if err := c.TriggerUpdate("certificates"); err != nil {
+		c.logs.WarnNoTrigger("Failed to trigger certificates update", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
 // GetLogs returns the logs component for service logging.
 func (c *Client) GetLogs() *standard.RecentLogs {
 	return c.logs
@@ -220,6 +427,11 @@ func (c *Client) GetConnectivity() *standard.ConnectivityTracker {
 	return c.connectivity
 }
 
+// GetProber returns the connectivity prober for adding probe targets at runtime.
+func (c *Client) GetProber() *standard.ConnectivityProber {
+	return c.prober
+}
+
 // GetCertMonitor returns the certificate monitor for expiry checking.
 func (c *Client) GetCertMonitor() *standard.CertificateMonitor {
 	return c.certMonitor
@@ -274,12 +486,24 @@ func (c *Client) Start() error {
 
 	c.running = true
 
+	// Restart after Stop: the previous run's context is cancelled for good
+	if c.ctx.Err() != nil {
+		c.stopChan = make(chan struct{})
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+	}
+
 	// Start Heartbeat System (timer-based)
 	c.startHeartbeatSystem()
 
 	// Start Update System (timer-based)
 	c.startUpdateSystem()
 
+	// Start active dependency probing (no-op without targets)
+	c.prober.Start()
+
+	// Start certificate watcher (CertDir changes + expiry thresholds)
+	c.certMonitor.Start()
+
 	// Startup complete - can now use logs component
 	c.logs.Info("Introspection client started", map[string]interface{}{
 		"heartbeat_interval_sec": HeartbeatIntervalSec,
@@ -288,17 +512,17 @@ func (c *Client) Start() error {
 	return nil
 }
 
-// Stop gracefully stops the client.
+// Stop gracefully stops the client (Start may be called again afterwards).
 func (c *Client) Stop() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if !c.running {
+		c.mu.Unlock()
 		return
 	}
 
 	c.running = false
 	close(c.stopChan)
+	c.cancel() // Abort in-flight sync requests
 
 	// Stop timers
 	if c.heartbeatTimer != nil {
@@ -307,6 +531,16 @@ func (c *Client) Stop() {
 	if c.updateTimer != nil {
 		c.updateTimer.Stop()
 	}
+	if c.retryTimer != nil {
+		c.retryTimer.Stop()
+		c.retryTimer = nil // A stopped timer never runs its own reset (Start may re-arm)
+	}
+	c.mu.Unlock()
+
+	// Stop probing and the certificate watcher outside the lock: both wait for in-flight
+	// probes/scans, which may log Warn (takes c.mu)
+	c.prober.Stop()
+	c.certMonitor.Stop()
 
 	c.logs.Info("Introspection client stopped", map[string]interface{}{
 		"entity_id": c.entityID,
@@ -319,7 +553,7 @@ func (c *Client) Stop() {
 
 // startHeartbeatSystem initializes the heartbeat timer.
 func (c *Client) startHeartbeatSystem() {
-	interval := time.Duration(HeartbeatIntervalSec) * time.Second
+	interval := c.heartbeatIntervalLocked()
 	c.heartbeatTimer = time.AfterFunc(interval, c.onHeartbeatFire)
 }
 
@@ -340,20 +574,33 @@ func (c *Client) onHeartbeatFire() {
 	go c.triggerSync("heartbeat-timer")
 
 	// Reset timer for next heartbeat
-	interval := time.Duration(HeartbeatIntervalSec) * time.Second
-	c.heartbeatTimer.Reset(interval)
+	c.heartbeatTimer.Reset(c.currentHeartbeatInterval())
 }
 
 // resetHeartbeatTimer resets the heartbeat timer (called on real activity).
 func (c *Client) resetHeartbeatTimer() {
-	interval := time.Duration(HeartbeatIntervalSec) * time.Second
 	c.mu.Lock()
 	if c.heartbeatTimer != nil {
-		c.heartbeatTimer.Reset(interval)
+		c.heartbeatTimer.Reset(c.heartbeatIntervalLocked())
 	}
 	c.mu.Unlock()
 }
 
+// currentHeartbeatInterval returns the heartbeat interval (59s unless introspection requested another).
+func (c *Client) currentHeartbeatInterval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.heartbeatIntervalLocked()
+}
+
+// heartbeatIntervalLocked is currentHeartbeatInterval with c.mu held.
+func (c *Client) heartbeatIntervalLocked() time.Duration {
+	if c.heartbeatInterval > 0 {
+		return c.heartbeatInterval
+	}
+	return time.Duration(HeartbeatIntervalSec) * time.Second
+}
+
 // ============================================================================
 // UPDATE SYSTEM (ADR-032: Section "2. Component Update System")
 // ============================================================================
@@ -452,39 +699,256 @@ func (c *Client) executeSyncLoop(source string) {
 }
 
 // executeSync performs the Three-Phase Sync Protocol with exponential backoff.
+// Failures are classified: retryable ones walk the backoff schedule, throttled ones wait for
+// Retry-After, permanent ones (e.g., 401/403) are retried only once per heartbeat interval.
+// Waits never block: the failure pauses syncs and deferSync schedules the retry, which Stop
+// cancels and into which triggers during the wait are merged (it collects the newest data).
 func (c *Client) executeSync(source string) {
-	// Retry loop with exponential backoff (prime numbers)
-	for {
-		err := c.performThreePhaseSync()
-		if err == nil {
-			// Success! Reset backoff
-			c.mu.Lock()
-			c.backoffIndex = 0
-			c.mu.Unlock()
-			return
+	// Stopped (Stop cancels the context)
+	ctx := c.runContext()
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Paused (backoff, retry_after, Retry-After, permanent failure): the deferred sync runs when the pause ends
+	if c.deferSync() {
+		return
+	}
+
+	startTime := time.Now()
+	c.stats.recordAttempt(startTime)
+
+	err := c.performThreePhaseSync()
+	if err == nil {
+		c.stats.recordSuccess(startTime)
+
+		// Success! Reset backoff
+		c.mu.Lock()
+		c.backoffIndex = 0
+		recovered := c.permanentFailure
+		c.permanentFailure = nil
+		c.mu.Unlock()
+
+		if recovered != nil {
+			c.logs.Info("Introspection accepts syncs again", map[string]interface{}{
+				"source":         source,
+				"previous_error": recovered.Error(),
+			})
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		return // Aborted by Stop - not a failure
+	}
+
+	class, retryAfter := classifyFailure(err)
+	c.stats.recordFailure(err, class)
+	switch {
+	case class == FailurePermanent:
+		// Do not hammer: next attempt with the next heartbeat (logged once per distinct error)
+		pause := c.currentHeartbeatInterval()
+		c.pauseSyncs(pause)
+		c.mu.Lock()
+		repeated := c.permanentFailure != nil && c.permanentFailure.Error() == err.Error()
+		c.permanentFailure = err
+		c.mu.Unlock()
+
+		if !repeated {
+			c.logs.ErrorNoTrigger("Introspection rejected sync, retrying once per heartbeat interval", map[string]interface{}{
+				"source":   source,
+				"error":    err.Error(),
+				"class":    string(class),
+				"retry_in": pause.String(),
+			})
 		}
 
-		// Failure - apply backoff
+	case class == FailureThrottled && retryAfter > 0:
+		c.pauseSyncs(retryAfter)
+		c.logs.WarnNoTrigger("Introspection throttled sync, waiting for Retry-After", map[string]interface{}{
+			"source":      source,
+			"error":       err.Error(),
+			"retry_after": retryAfter.String(),
+		})
+
+	default:
+		// Retryable (or throttled without Retry-After) - apply backoff (prime numbers by default)
 		c.mu.Lock()
 		backoffDuration := c.getBackoffDuration()
 		c.backoffIndex++
 		c.mu.Unlock()
+		c.pauseSyncs(backoffDuration)
 
 		// Use ErrorNoTrigger to avoid feedback loop (sync fails → log → trigger sync → ...)
 		c.logs.ErrorNoTrigger("Sync failed, retrying with backoff", map[string]interface{}{
-			"source":         source,
-			"error":          err.Error(),
-			"backoff_sec":    backoffDuration.Seconds(),
-			"retry_in":       backoffDuration.String(),
+			"source":      source,
+			"error":       err.Error(),
+			"class":       string(class),
+			"backoff_sec": backoffDuration.Seconds(),
+			"retry_in":    backoffDuration.String(),
 		})
-		time.Sleep(backoffDuration)
 	}
+
+	c.deferSync() // Schedules the retry
 }
 
 // performThreePhaseSync executes the Three-Phase Sync Protocol (ADR-028).
 func (c *Client) performThreePhaseSync() error {
-	// === PHASE 1: Collect ALL component checksums ===
+	// Negotiate protocol features first (no-op until the hourly refresh)
+	c.ensureHandshake()
+
+	// === PHASE 1: Collect ALL component checksums (+ heartbeat) ===
 	allRegistered := c.registry.GetAllRegistered()
+	checksums, heartbeatComp := c.collectChecksums(allRegistered)
+
+	// === PHASE 1b (root hash mode): send roots + heartbeat, expand only differing entities ===
+	c.mu.Lock()
+	useRoots := c.config.RootHashSync && !c.rootHashUnsupported
+	c.mu.Unlock()
+	useRoots = useRoots && c.featureEnabled(FeatureRootHash)
+	partial := false
+	directives := &ServerDirectives{} // Merged over all responses of this sync, applied once
+	if useRoots {
+		expand, rootDirectives, err := c.sendRoots(checksums, heartbeatComp)
+		switch {
+		case err == errRootsUnsupported:
+			// Older server: full checksums from now on
+			c.mu.Lock()
+			c.rootHashUnsupported = true
+			c.mu.Unlock()
+			c.logs.WarnNoTrigger("Introspection does not support root hash sync, sending full checksums", map[string]interface{}{
+				"endpoint": "/sync/roots",
+			})
+		case err != nil:
+			return fmt.Errorf("root phase failed: %w", err)
+		case rootDirectives.ResendAll || len(rootDirectives.Refresh) > 0:
+			// Resends go through the full checksum phase, as without root hash mode
+			directives.merge(rootDirectives)
+		default:
+			directives.merge(rootDirectives)
+			expanded := make(map[string]map[string]string, len(expand))
+			for _, entityID := range expand {
+				if entityChecksums, ok := checksums[entityID]; ok {
+					expanded[entityID] = entityChecksums
+				}
+			}
+			checksums = expanded
+			partial = true
+		}
+	}
+
+	// === PHASE 2: Send checksums, receive needed component IDs ===
+	// Skipped if all roots match (heartbeat was delivered inline)
+	var neededComponents map[string][]string
+	if !partial || len(checksums) > 0 {
+		payload := map[string]interface{}{
+			"service":   c.config.ServiceName,
+			"server":    c.config.Server,
+			"checksums": checksums,
+		}
+		if partial {
+			// Only entities whose root differed - the others must not be treated as ghosts
+			payload["partial"] = true
+		}
+
+		needed, checksumDirectives, err := c.sendChecksums(payload)
+		if err != nil {
+			c.applyDirectives(directives) // Those of /sync/roots still count
+			return fmt.Errorf("checksum phase failed: %w", err)
+		}
+		neededComponents = needed
+		directives.merge(checksumDirectives)
+	}
+	c.applyDirectives(directives)
+
+	// Server-directed resends (resend_all / refresh): sent in full even if the checksum matches
+	forced := directives.forcedComponents(allRegistered, c.entityID)
+	for entityID, componentIDs := range forced {
+		for componentID := range componentIDs {
+			if !slices.Contains(neededComponents[entityID], componentID) {
+				if neededComponents == nil {
+					neededComponents = make(map[string][]string)
+				}
+				neededComponents[entityID] = append(neededComponents[entityID], componentID)
+			}
+		}
+	}
+
+	// === PHASE 3: Send only needed components ===
+	if len(neededComponents) > 0 {
+		componentsToSend := make(map[string][]component.Component)
+		fullComponents := make(map[string]map[string]component.Component) // For delta fallback + acknowledgement
+		useDelta := c.config.DeltaEncoding && c.featureEnabled(FeatureDelta)
+
+		for entityID, componentIDs := range neededComponents {
+			for _, componentID := range componentIDs {
+				var comp component.Component
+				if componentID == "heartbeat" && entityID == c.entityID {
+					comp = heartbeatComp
+				} else {
+					var err error
+					comp, err = c.registry.Collect(entityID, componentID)
+					if err != nil {
+						c.logs.Warn("Failed to collect component for sync", map[string]interface{}{
+							"entity_id":    entityID,
+							"component_id": componentID,
+							"error":        err.Error(),
+						})
+						continue
+					}
+				}
+
+				if fullComponents[entityID] == nil {
+					fullComponents[entityID] = make(map[string]component.Component)
+				}
+				fullComponents[entityID][componentID] = comp
+
+				// Delta mode: JSON patch against the acknowledged base (if smaller)
+				if useDelta && !forced[entityID][componentID] {
+					comp = c.deltaComponent(entityID, comp)
+				}
+
+				if componentsToSend[entityID] == nil {
+					componentsToSend[entityID] = []component.Component{}
+				}
+				componentsToSend[entityID] = append(componentsToSend[entityID], comp)
+			}
+		}
+
+		missingBase, err := c.sendComponents(componentsToSend)
+		if err != nil {
+			return fmt.Errorf("data phase failed: %w", err)
+		}
+
+		// Introspection does not have the base of some patches: resend those in full
+		if len(missingBase) > 0 {
+			resend := make(map[string][]component.Component)
+			for entityID, componentIDs := range missingBase {
+				for _, componentID := range componentIDs {
+					c.registry.ForgetAckedBase(entityID, componentID)
+					if comp, ok := fullComponents[entityID][componentID]; ok {
+						resend[entityID] = append(resend[entityID], comp)
+					}
+				}
+			}
+			if _, err := c.sendComponents(resend); err != nil {
+				return fmt.Errorf("data phase (full resend) failed: %w", err)
+			}
+		}
+
+		// Acknowledged data becomes the base for the next patch
+		for entityID, comps := range fullComponents {
+			for componentID, comp := range comps {
+				c.registry.MarkSynced(entityID, componentID, comp.Checksum)
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectChecksums collects all registered components (Phase 1) and builds the heartbeat.
+// Returns entityID -> componentID -> checksum (heartbeat included) and the heartbeat component.
+func (c *Client) collectChecksums(allRegistered map[string][]string) (map[string]map[string]string, component.Component) {
 	checksums := make(map[string]map[string]string) // entityID -> componentID -> checksum
 
 	for entityID, componentIDs := range allRegistered {
@@ -522,70 +986,138 @@ func (c *Client) performThreePhaseSync() error {
 	}
 	checksums[c.entityID]["heartbeat"] = heartbeatComp.Checksum
 
-	// === PHASE 2: Send checksums, receive needed component IDs ===
+	return checksums, heartbeatComp
+}
+
+// errRootsUnsupported is returned by sendRoots when the server has no /sync/roots endpoint.
+var errRootsUnsupported = errors.New("root hash sync not supported by server")
+
+// sendRoots sends entity root hashes, the overall root and the heartbeat (root hash mode).
+// The heartbeat is excluded from the roots (it changes on every sync) and delivered inline.
+// Returns the entity IDs whose root differs on the server (empty = nothing changed) and the
+// server directives (a quiet client never reaches /sync/checksums; the caller applies them).
+func (c *Client) sendRoots(checksums map[string]map[string]string, heartbeat component.Component) ([]string, *ServerDirectives, error) {
+	url := c.config.IntrospectionURL + "/sync/roots"
+
+	roots, root := c.rootHashes(checksums)
+
 	payload := map[string]interface{}{
 		"service":   c.config.ServiceName,
 		"server":    c.config.Server,
-		"checksums": checksums,
+		"root":      root,
+		"roots":     roots,
+		"heartbeat": heartbeat,
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal roots: %w", err)
 	}
 
-	neededComponents, err := c.sendChecksums(payload)
+	// Deadline for request + response body
+	ctx, cancel := c.phaseContext()
+	defer cancel()
+
+	// Track connectivity (start timer)
+	startTime := time.Now()
+
+	resp, err := c.postJSON(ctx, "roots", url, jsonData)
+	latency := time.Since(startTime)
+
 	if err != nil {
-		return fmt.Errorf("checksum phase failed: %w", err)
+		// Track failed request
+		c.connectivity.TrackFailure("introspection", c.config.IntrospectionURL, latency, err.Error())
+		c.logs.ErrorNoTrigger("Introspection sync failed", map[string]interface{}{
+			"phase":      "roots",
+			"error":      err.Error(),
+			"latency_ms": latency.Milliseconds(),
+		})
+		return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	// === PHASE 3: Send only needed components ===
-	if len(neededComponents) > 0 {
-		componentsToSend := make(map[string][]component.Component)
+	if resp.StatusCode == http.StatusNotFound {
+		// Server reachable, endpoint unknown (older introspection)
+		c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+		return nil, nil, errRootsUnsupported
+	}
 
-		for entityID, componentIDs := range neededComponents {
-			for _, componentID := range componentIDs {
-				var comp component.Component
-				if componentID == "heartbeat" && entityID == c.entityID {
-					comp = heartbeatComp
-				} else {
-					var err error
-					comp, err = c.registry.Collect(entityID, componentID)
-					if err != nil {
-						c.logs.Warn("Failed to collect component for sync", map[string]interface{}{
-							"entity_id":    entityID,
-							"component_id": componentID,
-							"error":        err.Error(),
-						})
-						continue
-					}
-				}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		errorMsg := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
+		// Track failed request
+		c.connectivity.TrackFailure("introspection", c.config.IntrospectionURL, latency, errorMsg)
+		c.logs.ErrorNoTrigger("Introspection sync failed", map[string]interface{}{
+			"phase":      "roots",
+			"status":     resp.StatusCode,
+			"error":      string(body),
+			"latency_ms": latency.Milliseconds(),
+		})
+		return nil, nil, newSyncError("roots", resp, body)
+	}
 
-				if componentsToSend[entityID] == nil {
-					componentsToSend[entityID] = []component.Component{}
-				}
-				componentsToSend[entityID] = append(componentsToSend[entityID], comp)
-			}
-		}
+	var response struct {
+		Expand     []string         `json:"expand"` // Entity IDs whose root differs
+		Directives ServerDirectives `json:"directives"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		// Track successful HTTP but failed decode
+		c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+		c.logs.ErrorNoTrigger("Failed to decode introspection response", map[string]interface{}{
+			"phase":      "roots",
+			"error":      err.Error(),
+			"latency_ms": latency.Milliseconds(),
+		})
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
 
-		err = c.sendComponents(componentsToSend)
-		if err != nil {
-			return fmt.Errorf("data phase failed: %w", err)
+	// Track successful request
+	c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+	c.stats.recordItems("roots", len(roots))
+
+	return response.Expand, &response.Directives, nil
+}
+
+// rootHashes computes entity roots and the overall root, excluding the own heartbeat.
+func (c *Client) rootHashes(checksums map[string]map[string]string) (map[string]string, string) {
+	withoutHeartbeat := make(map[string]map[string]string, len(checksums))
+	for entityID, entityChecksums := range checksums {
+		withoutHeartbeat[entityID] = make(map[string]string, len(entityChecksums))
+		for componentID, checksum := range entityChecksums {
+			if entityID == c.entityID && componentID == "heartbeat" {
+				continue
+			}
+			withoutHeartbeat[entityID][componentID] = checksum
 		}
 	}
-
-	return nil
+	return component.EntityRoots(withoutHeartbeat)
 }
 
 // sendChecksums sends checksums to introspection (Phase 1).
-// Returns map of entityID -> []componentID that introspection needs.
-func (c *Client) sendChecksums(payload map[string]interface{}) (map[string][]string, error) {
+// Returns map of entityID -> []componentID that introspection needs, plus the server directives
+// (applied by the caller together with those of /sync/roots).
+func (c *Client) sendChecksums(payload map[string]interface{}) (map[string][]string, *ServerDirectives, error) {
 	url := c.config.IntrospectionURL + "/sync/checksums"
 
+	checksumCount := 0
+	if checksums, ok := payload["checksums"].(map[string]map[string]string); ok {
+		for _, entityChecksums := range checksums {
+			checksumCount += len(entityChecksums)
+		}
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal checksums: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal checksums: %w", err)
 	}
 
+	// Deadline for request + response body
+	ctx, cancel := c.phaseContext()
+	defer cancel()
+
 	// Track connectivity (start timer)
 	startTime := time.Now()
 
-	resp, err := c.http.Post(url, "application/json", bytes.NewReader(jsonData))
+	resp, err := c.postJSON(ctx, "checksums", url, jsonData)
 	latency := time.Since(startTime)
 
 	if err != nil {
@@ -596,7 +1128,7 @@ func (c *Client) sendChecksums(payload map[string]interface{}) (map[string][]str
 			"error":      err.Error(),
 			"latency_ms": latency.Milliseconds(),
 		})
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -611,11 +1143,12 @@ func (c *Client) sendChecksums(payload map[string]interface{}) (map[string][]str
 			"error":      string(body),
 			"latency_ms": latency.Milliseconds(),
 		})
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, nil, newSyncError("checksums", resp, body)
 	}
 
 	var response struct {
-		Needed map[string][]string `json:"needed"` // entityID -> []componentID
+		Needed     map[string][]string `json:"needed"` // entityID -> []componentID
+		Directives ServerDirectives    `json:"directives"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -626,19 +1159,49 @@ func (c *Client) sendChecksums(payload map[string]interface{}) (map[string][]str
 			"error":      err.Error(),
 			"latency_ms": latency.Milliseconds(),
 		})
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Track successful request
 	c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+	c.stats.recordItems("checksums", checksumCount)
+
+	return response.Needed, &response.Directives, nil
+}
+
+// deltaComponent replaces full data by a JSON patch against the acknowledged base,
+// if one exists and the patch is smaller. Returns comp unchanged otherwise.
+func (c *Client) deltaComponent(entityID string, comp component.Component) component.Component {
+	data, ok := comp.Data.(json.RawMessage)
+	if !ok {
+		return comp // Heartbeat (not in registry)
+	}
+	baseChecksum, base, ok := c.registry.AckedBase(entityID, comp.ID)
+	if !ok || baseChecksum == comp.Checksum {
+		return comp // No base, or introspection lost data it acknowledged
+	}
 
-	return response.Needed, nil
+	patch, err := component.Diff(base, data)
+	if err != nil {
+		return comp
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil || len(patchJSON) >= len(data) {
+		return comp
+	}
+	return component.NewPatch(comp, baseChecksum, patch)
 }
 
 // sendComponents sends component data to introspection (Phase 3).
-func (c *Client) sendComponents(components map[string][]component.Component) error {
+// Returns entityID -> []componentID whose patch base introspection does not have (delta mode).
+func (c *Client) sendComponents(components map[string][]component.Component) (map[string][]string, error) {
 	url := c.config.IntrospectionURL + "/sync/components"
 
+	componentCount := 0
+	for _, comps := range components {
+		componentCount += len(comps)
+	}
+
 	payload := map[string]interface{}{
 		"service":    c.config.ServiceName,
 		"server":     c.config.Server,
@@ -647,13 +1210,17 @@ func (c *Client) sendComponents(components map[string][]component.Component) err
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal components: %w", err)
+		return nil, fmt.Errorf("failed to marshal components: %w", err)
 	}
 
+	// Deadline for request + response body
+	ctx, cancel := c.phaseContext()
+	defer cancel()
+
 	// Track connectivity (start timer)
 	startTime := time.Now()
 
-	resp, err := c.http.Post(url, "application/json", bytes.NewReader(jsonData))
+	resp, err := c.postJSON(ctx, "components", url, jsonData)
 	latency := time.Since(startTime)
 
 	if err != nil {
@@ -664,7 +1231,7 @@ func (c *Client) sendComponents(components map[string][]component.Component) err
 			"error":      err.Error(),
 			"latency_ms": latency.Milliseconds(),
 		})
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -679,37 +1246,138 @@ func (c *Client) sendComponents(components map[string][]component.Component) err
 			"error":      string(body),
 			"latency_ms": latency.Milliseconds(),
 		})
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, newSyncError("components", resp, body)
 	}
 
 	// Track successful request
 	c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+	c.stats.recordItems("components", componentCount)
 
-	return nil
+	// Delta mode: response lists patches whose base introspection does not have
+	if !c.config.DeltaEncoding {
+		return nil, nil
+	}
+	var response struct {
+		MissingBase map[string][]string `json:"missing_base"` // entityID -> []componentID
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil && err != io.EOF {
+		// Not fatal: data was accepted, patches are simply not confirmed
+		c.logs.WarnNoTrigger("Failed to decode introspection components response", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return response.MissingBase, nil
+}
+
+// phaseContext returns the context of one sync request: PhaseTimeout deadline, cancelled by Stop.
+func (c *Client) phaseContext() (context.Context, context.CancelFunc) {
+	timeout := c.config.PhaseTimeout
+	if timeout == 0 {
+		timeout = DefaultPhaseTimeout
+	}
+	return context.WithTimeout(c.runContext(), timeout)
+}
+
+// runContext returns the context of the current run (cancelled by Stop, recreated by Start).
+func (c *Client) runContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ctx
+}
+
+// postJSON posts a JSON payload, compressed with the negotiated encoding.
+// A 415 response downgrades the encoding (zstd → gzip → uncompressed) and retries immediately.
+func (c *Client) postJSON(ctx context.Context, phase, url string, jsonData []byte) (*http.Response, error) {
+	for {
+		body, encoding, err := c.compressor.Encode(jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress payload: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(protocolHeader, strconv.Itoa(ProtocolVersion))
+		if encoding != transport.EncodingIdentity {
+			req.Header.Set("Content-Encoding", string(encoding))
+		}
+
+		c.stats.recordRequest(phase, len(body))
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnsupportedMediaType && c.compressor.Rejected(encoding, resp) {
+			resp.Body.Close()
+			c.logs.WarnNoTrigger("Introspection rejected compressed payload, falling back", map[string]interface{}{
+				"encoding": encoding.String(),
+				"fallback": c.compressor.Encoding().String(),
+			})
+			continue
+		}
+		return resp, nil
+	}
 }
 
 // ============================================================================
 // BACKOFF SYSTEM (ADR-032: Section "4. Exponential Backoff System")
 // ============================================================================
 
-// Prime number sequence for backoff (ADR-032).
+// Prime number sequence for backoff (ADR-032) - default for Config.BackoffSchedule.
 var backoffPrimes = []int{1, 2, 3, 5, 11, 23, 47, 61}
 
-// getBackoffDuration returns the current backoff duration.
-func (c *Client) getBackoffDuration() time.Duration {
-	// Cap at heartbeat interval (59s)
-	maxBackoff := HeartbeatIntervalSec
+// pauseSyncs delays all syncs by at least d (server retry_after, Retry-After, permanent failures).
+func (c *Client) pauseSyncs(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until := time.Now().Add(d); until.After(c.retryAfter) {
+		c.retryAfter = until
+	}
+}
 
-	index := c.backoffIndex
-	if index >= len(backoffPrimes) {
-		// Exhausted primes - use max backoff
-		return time.Duration(maxBackoff) * time.Second
+// deferSync reports whether syncs are paused by a server retry_after.
+// Schedules a single sync for when the pause ends (triggers during the pause are absorbed).
+func (c *Client) deferSync() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wait := time.Until(c.retryAfter)
+	if wait <= 0 {
+		return false
+	}
+	if c.retryTimer == nil && c.ctx.Err() == nil {
+		var timer *time.Timer
+		timer = time.AfterFunc(wait, func() {
+			c.mu.Lock()
+			if c.retryTimer == timer { // Not replaced after a Stop/Start
+				c.retryTimer = nil
+			}
+			c.mu.Unlock()
+			c.triggerSync("deferred")
+		})
+		c.retryTimer = timer
 	}
+	return true
+}
 
-	backoffSec := backoffPrimes[index]
-	if backoffSec > maxBackoff {
-		backoffSec = maxBackoff
+// getBackoffDuration returns the current backoff duration (c.mu held).
+func (c *Client) getBackoffDuration() time.Duration {
+	// Cap at heartbeat interval (59s unless introspection requested another)
+	maxBackoff := c.heartbeatIntervalLocked()
+
+	schedule := c.config.BackoffSchedule
+	if len(schedule) == 0 {
+		schedule = make([]time.Duration, len(backoffPrimes))
+		for i, prime := range backoffPrimes {
+			schedule[i] = time.Duration(prime) * time.Second
+		}
 	}
 
-	return time.Duration(backoffSec) * time.Second
+	// Exhausted schedule - repeat the last delay
+	index := min(c.backoffIndex, len(schedule)-1)
+	return min(schedule[index], maxBackoff)
 }
@@ -14,6 +14,10 @@ type Component struct {
 	Type     string      `json:"type"`
 	Checksum string      `json:"checksum"`
 	Data     interface{} `json:"data"`
+
+	// Delta mode (see patch.go): Patch applies to the data introspection acknowledged as BaseChecksum
+	BaseChecksum string    `json:"base_checksum,omitempty"`
+	Patch        []PatchOp `json:"patch,omitempty"`
 }
 
 // New creates a new component with automatic checksum calculation.
@@ -0,0 +1,184 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is a single RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string          `json:"op"` // "add", "remove" or "replace"
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// NewPatch creates a component that carries a JSON Patch against baseChecksum instead of full data.
+// Checksum is the checksum of the full data after applying the patch.
+func NewPatch(full Component, baseChecksum string, patch []PatchOp) Component {
+	return Component{
+		ID:           full.ID,
+		Type:         full.Type,
+		Checksum:     full.Checksum,
+		BaseChecksum: baseChecksum,
+		Patch:        patch,
+	}
+}
+
+// Diff computes an RFC 6902 JSON Patch transforming base into target (both JSON documents).
+// Arrays that grew at the end and/or lost elements at the front (ringbuffers) become remove/add
+// operations instead of a full replacement.
+func Diff(base, target []byte) ([]PatchOp, error) {
+	a, err := decodeJSON(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base: %w", err)
+	}
+	b, err := decodeJSON(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	ops := []PatchOp{}
+	if err := diffValue("", a, b, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// decodeJSON decodes with json.Number so numbers round-trip exactly.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// diffValue appends operations transforming a into b at path.
+func diffValue(path string, a, b interface{}, ops *[]PatchOp) error {
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+
+	switch aTyped := a.(type) {
+	case map[string]interface{}:
+		if bTyped, ok := b.(map[string]interface{}); ok {
+			return diffObject(path, aTyped, bTyped, ops)
+		}
+	case []interface{}:
+		if bTyped, ok := b.([]interface{}); ok {
+			return diffArray(path, aTyped, bTyped, ops)
+		}
+	}
+	return appendOp(ops, "replace", path, b)
+}
+
+// diffObject diffs two objects key by key (sorted for deterministic patches).
+func diffObject(path string, a, b map[string]interface{}, ops *[]PatchOp) error {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + escapePointer(key)
+		aValue, inA := a[key]
+		bValue, inB := b[key]
+		var err error
+		switch {
+		case !inB:
+			err = appendOp(ops, "remove", childPath, nil)
+		case !inA:
+			err = appendOp(ops, "add", childPath, bValue)
+		default:
+			err = diffValue(childPath, aValue, bValue, ops)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffArray handles append/shift (a[k:] is a prefix of b), prepend/truncate (a[:n-k] is a suffix of b)
+// and same-length arrays (element-wise). Everything else is replaced.
+func diffArray(path string, a, b []interface{}, ops *[]PatchOp) error {
+	// Append (+ dropped from the front): remove k at index 0, add the rest at the end
+	for k := 0; k < len(a); k++ {
+		kept := a[k:]
+		if len(kept) <= len(b) && reflect.DeepEqual(kept, b[:len(kept)]) {
+			for i := 0; i < k; i++ {
+				if err := appendOp(ops, "remove", path+"/0", nil); err != nil {
+					return err
+				}
+			}
+			for _, value := range b[len(kept):] {
+				if err := appendOp(ops, "add", path+"/-", value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	// Prepend (+ dropped from the end): remove k from the end, add the new ones at the front
+	for k := 0; k < len(a); k++ {
+		kept := a[:len(a)-k]
+		if len(kept) <= len(b) && reflect.DeepEqual(kept, b[len(b)-len(kept):]) {
+			for i := len(a) - 1; i >= len(kept); i-- {
+				if err := appendOp(ops, "remove", path+"/"+strconv.Itoa(i), nil); err != nil {
+					return err
+				}
+			}
+			for i := len(b) - len(kept) - 1; i >= 0; i-- {
+				if err := appendOp(ops, "add", path+"/0", b[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	// Same length: element-wise (e.g., one entry changed in place)
+	if len(a) == len(b) {
+		for i := range a {
+			if err := diffValue(path+"/"+strconv.Itoa(i), a[i], b[i], ops); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return appendOp(ops, "replace", path, b)
+}
+
+// appendOp marshals value and appends the operation.
+func appendOp(ops *[]PatchOp, op, path string, value interface{}) error {
+	patchOp := PatchOp{Op: op, Path: path}
+	if op != "remove" {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		patchOp.Value = raw
+	}
+	*ops = append(*ops, patchOp)
+	return nil
+}
+
+// escapePointer escapes a key for use in a JSON Pointer (RFC 6901).
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,220 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   string
+		target string
+		want   string // Expected patch (empty = only round-trip checked)
+	}{
+		{
+			name:   "unchanged",
+			base:   `{"a": 1, "b": [1, 2]}`,
+			target: `{"b": [1, 2], "a": 1}`,
+			want:   `[]`,
+		},
+		{
+			name:   "replace scalar",
+			base:   `{"status": "ok"}`,
+			target: `{"status": "degraded"}`,
+			want:   `[{"op":"replace","path":"/status","value":"degraded"}]`,
+		},
+		{
+			name:   "add and remove keys (sorted)",
+			base:   `{"b": 1, "c": 2}`,
+			target: `{"a": 0, "c": 2}`,
+			want:   `[{"op":"add","path":"/a","value":0},{"op":"remove","path":"/b"}]`,
+		},
+		{
+			name:   "escaped keys",
+			base:   `{"a/b": 1, "m~n": 1}`,
+			target: `{"a/b": 2, "m~n": 2}`,
+			want:   `[{"op":"replace","path":"/a~1b","value":2},{"op":"replace","path":"/m~0n","value":2}]`,
+		},
+		{
+			name:   "ringbuffer append and shift",
+			base:   `{"entries": [1, 2, 3]}`,
+			target: `{"entries": [2, 3, 4, 5]}`,
+			want:   `[{"op":"remove","path":"/entries/0"},{"op":"add","path":"/entries/-","value":4},{"op":"add","path":"/entries/-","value":5}]`,
+		},
+		{
+			name:   "prepend and truncate",
+			base:   `[1, 2, 3]`,
+			target: `[-1, 0, 1, 2]`,
+			want:   `[{"op":"remove","path":"/2"},{"op":"add","path":"/0","value":0},{"op":"add","path":"/0","value":-1}]`,
+		},
+		{
+			name:   "same length element-wise",
+			base:   `[{"id": 1, "n": 1}, {"id": 2, "n": 1}]`,
+			target: `[{"id": 1, "n": 1}, {"id": 2, "n": 2}]`,
+			want:   `[{"op":"replace","path":"/1/n","value":2}]`,
+		},
+		{
+			name:   "unrelated arrays replaced",
+			base:   `{"list": [1, 2, 3]}`,
+			target: `{"list": [7, 8]}`,
+			want:   `[{"op":"replace","path":"/list","value":[7,8]}]`,
+		},
+		{
+			name:   "type change",
+			base:   `{"value": {"nested": true}}`,
+			target: `{"value": [true]}`,
+			want:   `[{"op":"replace","path":"/value","value":[true]}]`,
+		},
+		{
+			name:   "root replaced",
+			base:   `{"a": 1}`,
+			target: `"gone"`,
+			want:   `[{"op":"replace","path":"","value":"gone"}]`,
+		},
+		{
+			name:   "large numbers exact",
+			base:   `{"bytes": 12345678901234567890}`,
+			target: `{"bytes": 12345678901234567891}`,
+			want:   `[{"op":"replace","path":"/bytes","value":12345678901234567891}]`,
+		},
+		{
+			name:   "nested mixed changes",
+			base:   `{"stats": {"count": 3, "errors": 1}, "entries": [{"m": "a"}, {"m": "b"}], "gone": null}`,
+			target: `{"stats": {"count": 4, "errors": 1, "warnings": 0}, "entries": [{"m": "b"}, {"m": "c"}, {"m": "d"}]}`,
+		},
+		{
+			name:   "empty array replaced",
+			base:   `{"entries": []}`,
+			target: `{"entries": [1, 2]}`,
+			want:   `[{"op":"replace","path":"/entries","value":[1,2]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := Diff([]byte(tt.base), []byte(tt.target))
+			if err != nil {
+				t.Fatalf("Diff: %v", err)
+			}
+
+			if tt.want != "" {
+				got, _ := json.Marshal(ops)
+				if string(got) != tt.want {
+					t.Errorf("patch = %s, want %s", got, tt.want)
+				}
+			}
+
+			// Round-trip: applying the patch to base yields target
+			patched, err := applyPatch(mustDecode(t, tt.base), ops)
+			if err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			if want := mustDecode(t, tt.target); !reflect.DeepEqual(patched, want) {
+				got, _ := json.Marshal(patched)
+				t.Errorf("patched = %s, want %s", got, tt.target)
+			}
+		})
+	}
+}
+
+func TestDiffInvalidJSON(t *testing.T) {
+	if _, err := Diff([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("invalid base: expected error")
+	}
+	if _, err := Diff([]byte(`{}`), []byte(`nope`)); err == nil {
+		t.Error("invalid target: expected error")
+	}
+}
+
+// mustDecode decodes like Diff (json.Number) so numbers compare exactly.
+func mustDecode(t *testing.T, data string) interface{} {
+	t.Helper()
+	value, err := decodeJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return value
+}
+
+// applyPatch applies add/remove/replace operations (the subset Diff emits) to doc.
+func applyPatch(doc interface{}, ops []PatchOp) (interface{}, error) {
+	for _, op := range ops {
+		var value interface{}
+		if op.Op != "remove" {
+			decoder := json.NewDecoder(bytes.NewReader(op.Value))
+			decoder.UseNumber()
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+		}
+
+		var tokens []string
+		if op.Path != "" {
+			for _, token := range strings.Split(op.Path, "/")[1:] {
+				tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+			}
+		}
+
+		var err error
+		doc, err = applyOp(doc, tokens, op.Op, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// applyOp applies one operation at the pointer tokens below doc and returns the updated doc.
+func applyOp(doc interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil // Whole document (remove leaves null)
+	}
+	token, rest := tokens[0], tokens[1:]
+
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		if len(rest) > 0 {
+			child, err := applyOp(container[token], rest, op, value)
+			container[token] = child
+			return container, err
+		}
+		if op == "remove" {
+			delete(container, token)
+		} else {
+			container[token] = value
+		}
+		return container, nil
+
+	case []interface{}:
+		if token == "-" && op == "add" && len(rest) == 0 {
+			return append(container, value), nil
+		}
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index > len(container) || (index == len(container) && op != "add") {
+			return nil, fmt.Errorf("invalid index %q", token)
+		}
+		if len(rest) > 0 {
+			child, err := applyOp(container[index], rest, op, value)
+			container[index] = child
+			return container, err
+		}
+		switch op {
+		case "add":
+			return append(container[:index], append([]interface{}{value}, container[index:]...)...), nil
+		case "remove":
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			container[index] = value
+			return container, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("cannot apply %s below %T", op, doc)
+	}
+}
@@ -0,0 +1,39 @@
+package component
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// RootHash aggregates checksums into one hash: SHA256 over sorted "id:checksum\n" lines.
+// Used per entity (componentID -> checksum) and overall (entityID -> entity root).
+// Adding or removing an ID changes the root, so an equal root means an identical set.
+func RootHash(checksums map[string]string) string {
+	ids := make([]string, 0, len(checksums))
+	for id := range checksums {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var sb strings.Builder
+	for _, id := range ids {
+		sb.WriteString(id)
+		sb.WriteByte(':')
+		sb.WriteString(checksums[id])
+		sb.WriteByte('\n')
+	}
+
+	hash := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+// EntityRoots computes the root hash of each entity and the overall root over all entities.
+func EntityRoots(checksums map[string]map[string]string) (map[string]string, string) {
+	roots := make(map[string]string, len(checksums))
+	for entityID, components := range checksums {
+		roots[entityID] = RootHash(components)
+	}
+	return roots, RootHash(roots)
+}
@@ -0,0 +1,90 @@
+package component
+
+import "testing"
+
+func TestRootHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		checksums map[string]string
+		want      string
+	}{
+		{
+			name:      "empty",
+			checksums: map[string]string{},
+			want:      "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name:      "two components",
+			checksums: map[string]string{"a": "1", "b": "2"},
+			want:      "6408fdc5c36df5df57957e7b230e4b48c55b06ce849303e855d52f1356f2980e",
+		},
+		{
+			name:      "standard components",
+			checksums: map[string]string{"heartbeat": "x", "logs": "y"},
+			want:      "1ddd7a233563c7d6f56c50e175f407356eada817cca03bfd0b24188e0df446b8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Golden values: the server computes the same hash, so any change breaks root mode
+			if got := RootHash(tt.checksums); got != tt.want {
+				t.Errorf("RootHash = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootHashChanges(t *testing.T) {
+	base := map[string]string{"a": "1", "b": "2"}
+	root := RootHash(base)
+
+	tests := []struct {
+		name      string
+		checksums map[string]string
+	}{
+		{name: "checksum changed", checksums: map[string]string{"a": "1", "b": "3"}},
+		{name: "id added", checksums: map[string]string{"a": "1", "b": "2", "c": ""}},
+		{name: "id removed", checksums: map[string]string{"a": "1"}},
+		{name: "id renamed", checksums: map[string]string{"a": "1", "c": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if RootHash(tt.checksums) == root {
+				t.Errorf("RootHash(%v) equals RootHash(%v)", tt.checksums, base)
+			}
+		})
+	}
+}
+
+func TestEntityRoots(t *testing.T) {
+	checksums := map[string]map[string]string{
+		"service": {"heartbeat": "x", "logs": "y"},
+		"peer":    {"a": "1", "b": "2"},
+	}
+
+	roots, root := EntityRoots(checksums)
+	want := map[string]string{
+		"service": "1ddd7a233563c7d6f56c50e175f407356eada817cca03bfd0b24188e0df446b8",
+		"peer":    "6408fdc5c36df5df57957e7b230e4b48c55b06ce849303e855d52f1356f2980e",
+	}
+	for entityID, wantRoot := range want {
+		if roots[entityID] != wantRoot {
+			t.Errorf("roots[%s] = %s, want %s", entityID, roots[entityID], wantRoot)
+		}
+	}
+	if len(roots) != len(want) {
+		t.Errorf("len(roots) = %d, want %d", len(roots), len(want))
+	}
+	if overall := RootHash(roots); root != overall {
+		t.Errorf("root = %s, want RootHash(roots) = %s", root, overall)
+	}
+
+	// Stable across calls (map iteration order must not matter)
+	for range 10 {
+		if _, again := EntityRoots(checksums); again != root {
+			t.Fatalf("root changed between calls: %s != %s", again, root)
+		}
+	}
+}
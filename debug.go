@@ -0,0 +1,237 @@
+package introspection
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DebugAccess restricts the debug handler. At least one rule is required (the handler exposes
+// raw component data). A request is allowed if its remote address is in AllowedNetworks OR it
+// presents a client certificate verified by the server's TLS config with an allowed name.
+type DebugAccess struct {
+	AllowedNetworks    []string // CIDRs or IPs of r.RemoteAddr (e.g., "127.0.0.1", "10.0.0.0/8"); proxies are not trusted
+	AllowedClientNames []string // mTLS: CommonName or DNS SAN of the verified client certificate
+}
+
+// debugAccess is the parsed DebugAccess.
+type debugAccess struct {
+	networks []netip.Prefix
+	names    []string
+}
+
+// parse validates the rules.
+func (a DebugAccess) parse() (*debugAccess, error) {
+	if len(a.AllowedNetworks) == 0 && len(a.AllowedClientNames) == 0 {
+		return nil, fmt.Errorf("AllowedNetworks or AllowedClientNames required")
+	}
+
+	parsed := &debugAccess{names: a.AllowedClientNames}
+	for _, network := range a.AllowedNetworks {
+		if !strings.Contains(network, "/") {
+			addr, err := netip.ParseAddr(network)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q: %w", network, err)
+			}
+			parsed.networks = append(parsed.networks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", network, err)
+		}
+		parsed.networks = append(parsed.networks, prefix.Masked())
+	}
+	return parsed, nil
+}
+
+// allows reports whether the request matches a rule.
+func (a *debugAccess) allows(r *http.Request) bool {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if addr, err := netip.ParseAddr(host); err == nil {
+			addr = addr.Unmap() // IPv4-mapped IPv6 (dual-stack listeners)
+			for _, network := range a.networks {
+				if network.Contains(addr) {
+					return true
+				}
+			}
+		}
+	}
+
+	// Only chains the server verified count (VerifyClientCertIfGiven / RequireAndVerifyClientCert)
+	if r.TLS != nil && len(a.names) > 0 {
+		for _, chain := range r.TLS.VerifiedChains {
+			if len(chain) > 0 && a.allowsCertificate(chain[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowsCertificate matches CommonName and DNS SANs against the allowed names.
+func (a *debugAccess) allowsCertificate(cert *x509.Certificate) bool {
+	if slices.Contains(a.names, cert.Subject.CommonName) {
+		return true
+	}
+	for _, name := range cert.DNSNames {
+		if slices.Contains(a.names, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DebugHandler returns an opt-in http.Handler showing exactly what the client collected and would sync:
+//
+//	GET  ?                                   all entities/components: checksum, last_update, last_sync, raw JSON
+//	POST ?action=dry-run                     collects all components and returns the checksum payload (nothing is sent)
+//	POST ?action=collect[&entity=&component=] calls the provider(s) now and returns the result (no sync triggered)
+//
+// Actions that call providers require POST. Requests not matching access get 403.
+func (c *Client) DebugHandler(access DebugAccess) (http.Handler, error) {
+	rules, err := access.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid DebugAccess: %w", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rules.allows(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		action := r.URL.Query().Get("action")
+		var data interface{}
+		var err error
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			data = c.debugComponents(nil)
+		case action == "dry-run" && r.Method == http.MethodPost:
+			data = c.debugDryRun()
+		case action == "collect" && r.Method == http.MethodPost:
+			data, err = c.debugCollect(r.URL.Query().Get("entity"), r.URL.Query().Get("component"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+		case action == "" || action == "dry-run" || action == "collect":
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		default:
+			http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(data)
+	}), nil
+}
+
+// debugComponents lists the cached state of all components (collectErrors: collect failures per component).
+func (c *Client) debugComponents(collectErrors map[string]map[string]string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	for entityID, components := range c.registry.GetStates() {
+		entityData := make(map[string]interface{}, len(components))
+		for componentID, state := range components {
+			componentData := map[string]interface{}{
+				"checksum":    state.Checksum,
+				"last_update": formatStatusTime(state.LastUpdate),
+				"last_sync":   formatStatusTime(state.LastSync),
+				"data":        nil,
+			}
+			if state.UpdateInterval != nil {
+				componentData["update_interval_sec"] = state.UpdateInterval.Seconds()
+			} else {
+				componentData["update_interval_sec"] = nil // OnlyTrigger
+			}
+			if raw, ok := c.registry.GetCachedData(entityID, componentID); ok {
+				componentData["data"] = json.RawMessage(raw)
+			}
+			if err, ok := collectErrors[entityID][componentID]; ok {
+				componentData["collect_error"] = err
+			}
+			entityData[componentID] = componentData
+		}
+		entities[entityID] = entityData
+	}
+
+	return map[string]interface{}{
+		"entity_id": c.entityID,
+		"entities":  entities,
+	}
+}
+
+// debugCollect calls the providers of one component, one entity or everything (empty IDs).
+func (c *Client) debugCollect(entityID, componentID string) (map[string]interface{}, error) {
+	registered := c.registry.GetAllRegistered()
+	if entityID != "" {
+		if _, ok := registered[entityID]; !ok {
+			return nil, fmt.Errorf("entity %s not registered", entityID)
+		}
+		registered = map[string][]string{entityID: registered[entityID]}
+	}
+	if componentID != "" {
+		if entityID == "" {
+			return nil, fmt.Errorf("component requires entity")
+		}
+		if !slices.Contains(registered[entityID], componentID) {
+			return nil, fmt.Errorf("component %s not registered for entity %s", componentID, entityID)
+		}
+		registered[entityID] = []string{componentID}
+	}
+
+	collectErrors := make(map[string]map[string]string)
+	for id, componentIDs := range registered {
+		for _, cid := range componentIDs {
+			if _, err := c.registry.Collect(id, cid); err != nil {
+				if collectErrors[id] == nil {
+					collectErrors[id] = make(map[string]string)
+				}
+				collectErrors[id][cid] = err.Error()
+			}
+		}
+	}
+
+	data := c.debugComponents(collectErrors)
+	data["collected_at"] = formatStatusTime(time.Now())
+	return data, nil
+}
+
+// debugDryRun builds the Phase 2 payload exactly like a sync (collects all components) without sending it.
+func (c *Client) debugDryRun() map[string]interface{} {
+	checksums, heartbeat := c.collectChecksums(c.registry.GetAllRegistered())
+
+	data := map[string]interface{}{
+		"endpoint": c.config.IntrospectionURL + "/sync/checksums",
+		"payload": map[string]interface{}{
+			"service":   c.config.ServiceName,
+			"server":    c.config.Server,
+			"checksums": checksums,
+		},
+		"heartbeat": heartbeat,
+	}
+
+	// Root hash mode: what /sync/roots would receive first
+	c.mu.Lock()
+	useRoots := c.config.RootHashSync && !c.rootHashUnsupported
+	c.mu.Unlock()
+	if useRoots && c.featureEnabled(FeatureRootHash) {
+		roots, root := c.rootHashes(checksums)
+		data["roots"] = map[string]interface{}{
+			"endpoint": c.config.IntrospectionURL + "/sync/roots",
+			"root":     root,
+			"roots":    roots,
+		}
+	}
+	return data
+}
@@ -0,0 +1,154 @@
+package introspection
+
+import (
+	"slices"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/standard"
+)
+
+// Bounds for server-requested values (a misbehaving server must not silence or flood clients).
+const (
+	minHeartbeatInterval = 5 * time.Second
+	maxHeartbeatInterval = 10 * time.Minute
+	maxRetryAfter        = 10 * time.Minute
+)
+
+// ServerDirectives are optional instructions in the /sync/roots and /sync/checksums responses ("directives").
+// Heartbeat interval and log level are state (absent = default), the others are one-off requests.
+// The directives of all responses of one sync are merged and applied once.
+type ServerDirectives struct {
+	HeartbeatIntervalSec int                 `json:"heartbeat_interval_sec"` // 0 = HeartbeatIntervalSec
+	ResendAll            bool                `json:"resend_all"`             // Send every component in full (e.g., server lost its state)
+	RetryAfterSec        int                 `json:"retry_after"`            // Load shedding: no sync before this many seconds
+	Refresh              map[string][]string `json:"refresh"`                // entityID -> []componentID to send in full now
+	LogLevel             standard.LogLevel   `json:"log_level"`              // Minimum level kept in recent-logs (empty = keep all)
+}
+
+// merge adds the directives of a later response of the same sync: state directives take the
+// value of the last response that sets them, one-off requests accumulate.
+func (d *ServerDirectives) merge(other *ServerDirectives) {
+	if other.HeartbeatIntervalSec != 0 {
+		d.HeartbeatIntervalSec = other.HeartbeatIntervalSec
+	}
+	if other.LogLevel != "" {
+		d.LogLevel = other.LogLevel
+	}
+	d.RetryAfterSec = max(d.RetryAfterSec, other.RetryAfterSec)
+	d.ResendAll = d.ResendAll || other.ResendAll
+	for entityID, componentIDs := range other.Refresh {
+		if d.Refresh == nil {
+			d.Refresh = make(map[string][]string)
+		}
+		for _, componentID := range componentIDs {
+			if !slices.Contains(d.Refresh[entityID], componentID) {
+				d.Refresh[entityID] = append(d.Refresh[entityID], componentID)
+			}
+		}
+	}
+}
+
+// applyDirectives applies heartbeat interval, retry_after and log level (Phase 3 handles resends).
+// Changes are logged with Info (no sync trigger: we are inside the sync).
+func (c *Client) applyDirectives(directives *ServerDirectives) {
+	// Heartbeat System: requested interval (clamped), reset timer on change
+	interval := time.Duration(directives.HeartbeatIntervalSec) * time.Second
+	if directives.HeartbeatIntervalSec > 0 {
+		interval = min(max(interval, minHeartbeatInterval), maxHeartbeatInterval)
+	}
+	c.mu.Lock()
+	previousInterval := c.heartbeatInterval
+	c.heartbeatInterval = interval
+	c.mu.Unlock()
+	if interval != previousInterval {
+		c.resetHeartbeatTimer()
+		c.logs.Info("Introspection changed heartbeat interval", map[string]interface{}{
+			"requested_sec": directives.HeartbeatIntervalSec,
+			"interval_sec":  c.currentHeartbeatInterval().Seconds(),
+		})
+	}
+
+	// Backoff System: pause syncs (load shedding)
+	if directives.RetryAfterSec > 0 {
+		wait := min(time.Duration(directives.RetryAfterSec)*time.Second, maxRetryAfter)
+		c.pauseSyncs(wait)
+		c.logs.Info("Introspection requested sync pause", map[string]interface{}{
+			"retry_after_sec": wait.Seconds(),
+		})
+	}
+
+	// Log level override (invalid levels are ignored, keeping the current one; absent = no override)
+	if previous, level := c.logs.MinLevelOverride(), directives.LogLevel; level != previous {
+		if err := level.Validate(); level != "" && err != nil {
+			c.logs.WarnNoTrigger("Ignoring invalid log level from introspection", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			// Log the change under whichever level keeps INFO (old before, new after)
+			logChange := func() {
+				c.logs.Info("Introspection changed log level", map[string]interface{}{
+					"override": string(level), // Empty = all levels kept again
+				})
+			}
+			if keepsInfo(previous) {
+				logChange()
+				c.logs.SetMinLevelOverride(level)
+			} else {
+				c.logs.SetMinLevelOverride(level)
+				logChange()
+			}
+		}
+	}
+
+	if directives.ResendAll {
+		c.logs.Info("Introspection requested full resend", map[string]interface{}{
+			"entity_id": c.entityID,
+		})
+	}
+	if len(directives.Refresh) > 0 {
+		c.logs.Info("Introspection requested component refresh", map[string]interface{}{
+			"refresh": directives.Refresh,
+		})
+	}
+}
+
+// keepsInfo reports whether INFO entries are kept in recent-logs under the override level.
+func keepsInfo(level standard.LogLevel) bool {
+	return level == "" || level == standard.LevelDebug || level == standard.LevelInfo
+}
+
+// forcedComponents returns the registered components (plus the own heartbeat) that must be
+// sent in full: all of them for resend_all, the listed ones for refresh.
+func (d *ServerDirectives) forcedComponents(registered map[string][]string, ownEntityID string) map[string]map[string]bool {
+	forced := make(map[string]map[string]bool)
+	add := func(entityID, componentID string) {
+		if forced[entityID] == nil {
+			forced[entityID] = make(map[string]bool)
+		}
+		forced[entityID][componentID] = true
+	}
+
+	isRegistered := func(entityID, componentID string) bool {
+		if entityID == ownEntityID && componentID == "heartbeat" {
+			return true
+		}
+		return slices.Contains(registered[entityID], componentID)
+	}
+
+	if d.ResendAll {
+		for entityID, componentIDs := range registered {
+			for _, componentID := range componentIDs {
+				add(entityID, componentID)
+			}
+		}
+		add(ownEntityID, "heartbeat")
+	}
+	for entityID, componentIDs := range d.Refresh {
+		for _, componentID := range componentIDs {
+			if isRegistered(entityID, componentID) {
+				add(entityID, componentID)
+			}
+		}
+	}
+	return forced
+}
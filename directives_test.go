@@ -0,0 +1,59 @@
+package introspection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServerDirectivesMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*ServerDirectives // /sync/roots, then /sync/checksums
+		want      ServerDirectives
+	}{
+		{
+			name:      "state only in roots",
+			responses: []*ServerDirectives{{HeartbeatIntervalSec: 120, LogLevel: "WARN"}, {}},
+			want:      ServerDirectives{HeartbeatIntervalSec: 120, LogLevel: "WARN"},
+		},
+		{
+			name:      "state only in checksums",
+			responses: []*ServerDirectives{{}, {HeartbeatIntervalSec: 30, LogLevel: "ERROR"}},
+			want:      ServerDirectives{HeartbeatIntervalSec: 30, LogLevel: "ERROR"},
+		},
+		{
+			name:      "last response wins",
+			responses: []*ServerDirectives{{HeartbeatIntervalSec: 120, LogLevel: "WARN"}, {HeartbeatIntervalSec: 30, LogLevel: "DEBUG"}},
+			want:      ServerDirectives{HeartbeatIntervalSec: 30, LogLevel: "DEBUG"},
+		},
+		{
+			name:      "absent everywhere",
+			responses: []*ServerDirectives{{}, {}},
+			want:      ServerDirectives{},
+		},
+		{
+			name: "one-off requests accumulate",
+			responses: []*ServerDirectives{
+				{RetryAfterSec: 60, Refresh: map[string][]string{"svc": {"logs"}}},
+				{RetryAfterSec: 10, ResendAll: true, Refresh: map[string][]string{"svc": {"logs", "runtime"}, "peer": {"certificates"}}},
+			},
+			want: ServerDirectives{
+				RetryAfterSec: 60,
+				ResendAll:     true,
+				Refresh:       map[string][]string{"svc": {"logs", "runtime"}, "peer": {"certificates"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := &ServerDirectives{}
+			for _, response := range tt.responses {
+				merged.merge(response)
+			}
+			if !reflect.DeepEqual(*merged, tt.want) {
+				t.Errorf("merged = %+v, want %+v", *merged, tt.want)
+			}
+		})
+	}
+}
@@ -38,7 +38,7 @@ func main() {
 				{"name": "cache", "ok": true},
 			},
 		}
-	}, nil) // OnlyTrigger (no periodic updates)
+	}) // OnlyTrigger (no periodic updates)
 
 	// Start background systems (Heartbeat, Update, Sync)
 	if err := client.Start(); err != nil {
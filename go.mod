@@ -2,6 +2,11 @@ module github.com/st-keller/introspection-client/v2
 
 go 1.25
 
-require golang.org/x/net v0.33.0
+require (
+	github.com/klauspost/compress v1.17.11
+	golang.org/x/crypto v0.31.0
+	golang.org/x/net v0.33.0
+	software.sslmate.com/src/go-pkcs12 v0.4.0
+)
 
 require golang.org/x/text v0.21.0 // indirect
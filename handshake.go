@@ -0,0 +1,212 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/transport"
+)
+
+// ProtocolVersion is the sync protocol version spoken by this library (sent on every request).
+const ProtocolVersion = 2
+
+// LibraryVersion is the version of this library (sent in the hello and the introspection-client component).
+const LibraryVersion = "2.7.0"
+
+// Optional protocol features, advertised in the handshake and gated on server support.
+const (
+	FeatureCompressionGzip = "compression-gzip" // Content-Encoding: gzip request bodies
+	FeatureCompressionZstd = "compression-zstd" // Content-Encoding: zstd request bodies
+	FeatureDelta           = "delta"            // RFC 6902 patches in /sync/components (Config.DeltaEncoding)
+	FeatureRootHash        = "root-hash"        // /sync/roots short-circuit (Config.RootHashSync)
+)
+
+// protocolHeader carries ProtocolVersion on every sync request.
+const protocolHeader = "X-Introspection-Protocol"
+
+// handshakeRefreshInterval re-negotiates periodically (servers get upgraded while clients run).
+const handshakeRefreshInterval = time.Hour
+
+// ServerCapabilities is the result of the handshake with introspection.
+type ServerCapabilities struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	ServerVersion   string          `json:"server_version"`
+	Features        map[string]bool `json:"features"`
+	Legacy          bool            `json:"legacy"` // Server predates the handshake (404 on /sync/hello)
+	NegotiatedAt    time.Time       `json:"negotiated_at"`
+}
+
+// Supports reports whether the server advertised a feature (always false for legacy servers).
+func (s *ServerCapabilities) Supports(feature string) bool {
+	return s != nil && s.Features[feature]
+}
+
+// ServerCapabilities returns the capabilities of the last successful handshake (nil before the first).
+func (c *Client) ServerCapabilities() *ServerCapabilities {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capabilities
+}
+
+// featureEnabled reports whether an optional behaviour may be used (unknown capabilities = no).
+func (c *Client) featureEnabled(feature string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capabilities.Supports(feature)
+}
+
+// clientFeatures lists the features this client is configured to use.
+func (c *Client) clientFeatures() []string {
+	features := []string{}
+	switch c.config.Compression {
+	case transport.EncodingZstd:
+		features = append(features, FeatureCompressionZstd, FeatureCompressionGzip)
+	case transport.EncodingGzip:
+		features = append(features, FeatureCompressionGzip)
+	}
+	if c.config.DeltaEncoding {
+		features = append(features, FeatureDelta)
+	}
+	if c.config.RootHashSync {
+		features = append(features, FeatureRootHash)
+	}
+	return features
+}
+
+// ensureHandshake negotiates capabilities before the first sync and then hourly.
+// Handshake failures never fail the sync: optional features stay off until it succeeds.
+func (c *Client) ensureHandshake() {
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	if capabilities != nil && time.Since(capabilities.NegotiatedAt) < handshakeRefreshInterval {
+		return
+	}
+
+	negotiated, err := c.sendHello()
+	if err != nil {
+		c.logs.WarnNoTrigger("Introspection handshake failed, optional features disabled", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.mu.Lock()
+	previous := c.capabilities
+	c.capabilities = negotiated
+	c.mu.Unlock()
+
+	// Compression follows the advertised encodings (legacy servers: 415 fallback only)
+	if !negotiated.Legacy {
+		accepted := []transport.Encoding{}
+		if negotiated.Supports(FeatureCompressionZstd) {
+			accepted = append(accepted, transport.EncodingZstd)
+		}
+		if negotiated.Supports(FeatureCompressionGzip) {
+			accepted = append(accepted, transport.EncodingGzip)
+		}
+		c.compressor.Negotiate(accepted)
+	}
+
+	// Log only changes (hourly refresh is usually a no-op)
+	if previous == nil || previous.ProtocolVersion != negotiated.ProtocolVersion ||
+		previous.Legacy != negotiated.Legacy || fmt.Sprint(previous.Features) != fmt.Sprint(negotiated.Features) {
+		c.logs.Info("Introspection capabilities negotiated", map[string]interface{}{
+			"protocol_version": negotiated.ProtocolVersion,
+			"server_version":   negotiated.ServerVersion,
+			"features":         negotiated.Features,
+			"legacy":           negotiated.Legacy,
+			"compression":      c.compressor.Encoding().String(),
+		})
+	}
+}
+
+// sendHello advertises library version, protocol version and features; returns the server's capabilities.
+func (c *Client) sendHello() (*ServerCapabilities, error) {
+	url := c.config.IntrospectionURL + "/sync/hello"
+
+	payload := map[string]interface{}{
+		"service":          c.config.ServiceName,
+		"server":           c.config.Server,
+		"entity_id":        c.entityID,
+		"library":          "introspection-client-go",
+		"library_version":  LibraryVersion,
+		"protocol_version": ProtocolVersion,
+		"features":         c.clientFeatures(),
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hello: %w", err)
+	}
+
+	// Uncompressed: encodings are not negotiated yet
+	ctx, cancel := c.phaseContext()
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(protocolHeader, strconv.Itoa(ProtocolVersion))
+
+	// Track connectivity (start timer)
+	startTime := time.Now()
+
+	resp, err := c.http.Do(req)
+	latency := time.Since(startTime)
+
+	if err != nil {
+		// Track failed request
+		c.connectivity.TrackFailure("introspection", c.config.IntrospectionURL, latency, err.Error())
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Server predates the handshake: protocol version 1, no optional features
+		c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+		return &ServerCapabilities{
+			ProtocolVersion: 1,
+			Features:        map[string]bool{},
+			Legacy:          true,
+			NegotiatedAt:    time.Now(),
+		}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		errorMsg := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
+		// Track failed request
+		c.connectivity.TrackFailure("introspection", c.config.IntrospectionURL, latency, errorMsg)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		ProtocolVersion int      `json:"protocol_version"`
+		ServerVersion   string   `json:"server_version"`
+		Features        []string `json:"features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Track successful request
+	c.connectivity.TrackSuccess("introspection", c.config.IntrospectionURL, latency)
+
+	capabilities := &ServerCapabilities{
+		ProtocolVersion: response.ProtocolVersion,
+		ServerVersion:   response.ServerVersion,
+		Features:        make(map[string]bool, len(response.Features)),
+		NegotiatedAt:    time.Now(),
+	}
+	for _, feature := range response.Features {
+		capabilities.Features[feature] = true
+	}
+	return capabilities, nil
+}
@@ -0,0 +1,93 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Health states reported by HealthHandler.
+const (
+	HealthOK       = "ok"       // Running, last sync succeeded
+	HealthDegraded = "degraded" // Running, syncs failing (below the strict threshold)
+	HealthFailing  = "failing"  // Strict mode: threshold of consecutive failures reached (503)
+	HealthStopped  = "stopped"  // Not started or stopped (503 in strict mode)
+)
+
+// HealthHandler returns an http.Handler serving the client state as JSON (Kubernetes probes,
+// load balancers): running state, sync status, registered entities/components with their
+// last update/sync times.
+// strictFailures > 0 enables strict mode: 503 when the client is not running or after that
+// many consecutive sync failures. strictFailures = 0 always answers 200 (informational).
+func (c *Client) HealthHandler(strictFailures int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		state, statusCode, data := c.health(strictFailures)
+		data["status"] = state
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(statusCode)
+		if r.Method == http.MethodHead {
+			return
+		}
+		json.NewEncoder(w).Encode(data)
+	})
+}
+
+// health evaluates the client state and builds the response body.
+func (c *Client) health(strictFailures int) (string, int, map[string]interface{}) {
+	status := c.Status()
+
+	state := HealthOK
+	switch {
+	case !status.Running:
+		state = HealthStopped
+	case strictFailures > 0 && status.ConsecutiveFailures >= strictFailures:
+		state = HealthFailing
+	case status.ConsecutiveFailures > 0:
+		state = HealthDegraded
+	}
+
+	statusCode := http.StatusOK
+	if strictFailures > 0 && (state == HealthStopped || state == HealthFailing) {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	entities := make(map[string]interface{})
+	for entityID, components := range c.registry.GetStates() {
+		entityData := make(map[string]interface{}, len(components))
+		for componentID, component := range components {
+			componentData := map[string]interface{}{
+				"checksum":    component.Checksum,
+				"last_update": formatStatusTime(component.LastUpdate),
+				"last_sync":   formatStatusTime(component.LastSync),
+			}
+			if component.UpdateInterval != nil {
+				componentData["update_interval_sec"] = component.UpdateInterval.Seconds()
+			} else {
+				componentData["update_interval_sec"] = nil // OnlyTrigger
+			}
+			entityData[componentID] = componentData
+		}
+		entities[entityID] = entityData
+	}
+
+	data := map[string]interface{}{
+		"entity_id":              c.entityID,
+		"service":                c.config.ServiceName,
+		"server":                 c.config.Server,
+		"running":                status.Running,
+		"strict_failures":        strictFailures,
+		"heartbeat_interval_sec": c.currentHeartbeatInterval().Seconds(),
+		"sync":                   status.data(),
+		"entities":               entities,
+		"checked_at":             formatStatusTime(time.Now()),
+	}
+	return state, statusCode, data
+}
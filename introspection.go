@@ -8,5 +8,5 @@
 //
 // Architecture: Services provide data, library handles ALL protocol complexity.
 //
-// Version: 2.5.0 (complete rewrite based on ADR-032)
+// Version: see LibraryVersion (2.x: complete rewrite based on ADR-032)
 package introspection
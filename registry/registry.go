@@ -42,6 +42,10 @@ type CachedComponent struct {
 	lastComponent component.Component // Cached component
 	lastSync      time.Time         // Last sync time (when sent to introspection)
 	lastUpdate    time.Time         // Last update time (when provider() called)
+
+	// Delta mode: data introspection acknowledged last (base for JSON patches)
+	ackedJSON     []byte
+	ackedChecksum string
 }
 
 // New creates a new Registry for the given entity.
@@ -147,23 +151,63 @@ func (r *Registry) Collect(entityID, componentID string) (component.Component, e
 		Data:     json.RawMessage(jsonData),
 	}
 
-	// Update cache (preserve lastSync if exists, update lastUpdate)
-	lastSync := time.Time{}
-	if cached != nil {
-		lastSync = cached.lastSync
-	}
-
-	r.cache[entityID][componentID] = &CachedComponent{
+	// Update cache (preserve lastSync and acknowledged base if exists, update lastUpdate)
+	updated := &CachedComponent{
 		lastRawJSON:   jsonData,
 		lastChecksum:  checksum,
 		lastComponent: comp,
-		lastSync:      lastSync,
 		lastUpdate:    now,
 	}
+	if cached != nil {
+		updated.lastSync = cached.lastSync
+		updated.ackedJSON = cached.ackedJSON
+		updated.ackedChecksum = cached.ackedChecksum
+	}
+	r.cache[entityID][componentID] = updated
 
 	return comp, nil
 }
 
+// MarkSynced records that introspection accepted a component with the given checksum.
+// The data becomes the base for delta patches (ignored if the cache moved on since sending).
+func (r *Registry) MarkSynced(entityID, componentID, checksum string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cached := r.cache[entityID][componentID]
+	if cached == nil {
+		return
+	}
+	cached.lastSync = time.Now()
+	if cached.lastChecksum == checksum {
+		cached.ackedJSON = cached.lastRawJSON
+		cached.ackedChecksum = checksum
+	}
+}
+
+// AckedBase returns the data and checksum introspection acknowledged last (ok=false if none).
+func (r *Registry) AckedBase(entityID, componentID string) (string, []byte, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cached := r.cache[entityID][componentID]
+	if cached == nil || cached.ackedChecksum == "" {
+		return "", nil, false
+	}
+	return cached.ackedChecksum, cached.ackedJSON, true
+}
+
+// ForgetAckedBase drops the delta base (introspection reported it does not have it).
+func (r *Registry) ForgetAckedBase(entityID, componentID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached := r.cache[entityID][componentID]; cached != nil {
+		cached.ackedJSON = nil
+		cached.ackedChecksum = ""
+	}
+}
+
 // GetDueComponents returns component IDs that need update (maxAge exceeded).
 // Uses lastUpdate (when provider() was called), not lastSync (when sent to introspection).
 func (r *Registry) GetDueComponents() map[string][]string {
@@ -250,6 +294,49 @@ func (r *Registry) GetAllRegistered() map[string][]string {
 	return registered
 }
 
+// ComponentState describes a registered component (for health and debug endpoints).
+type ComponentState struct {
+	UpdateInterval *update.Interval // nil = OnlyTrigger
+	Checksum       string           // Last collected checksum (empty = never collected)
+	LastUpdate     time.Time        // Last provider() call
+	LastSync       time.Time        // Last time introspection accepted the component data
+}
+
+// GetStates returns the state of all registered components per entity (no provider calls).
+func (r *Registry) GetStates() map[string]map[string]ComponentState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	states := make(map[string]map[string]ComponentState)
+
+	for entityID, entityConfigs := range r.configs {
+		states[entityID] = make(map[string]ComponentState, len(entityConfigs))
+		for componentID, config := range entityConfigs {
+			state := ComponentState{UpdateInterval: config.updateInterval}
+			if cached := r.cache[entityID][componentID]; cached != nil {
+				state.Checksum = cached.lastChecksum
+				state.LastUpdate = cached.lastUpdate
+				state.LastSync = cached.lastSync
+			}
+			states[entityID][componentID] = state
+		}
+	}
+
+	return states
+}
+
+// GetCachedData returns a copy of the last collected JSON of a component (ok=false if never collected).
+func (r *Registry) GetCachedData(entityID, componentID string) ([]byte, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cached := r.cache[entityID][componentID]
+	if cached == nil {
+		return nil, false
+	}
+	return bytes.Clone(cached.lastRawJSON), true
+}
+
 // GetOwnEntityID returns the entity ID of the service itself.
 func (r *Registry) GetOwnEntityID() string {
 	return r.ownEntityID
@@ -0,0 +1,116 @@
+package standard
+
+import (
+	"runtime/debug"
+	"sort"
+	"time"
+)
+
+// BuildInfo holds build and VCS metadata embedded by the Go toolchain (runtime/debug.ReadBuildInfo).
+type BuildInfo struct {
+	GoVersion     string            `json:"go_version"`
+	ModulePath    string            `json:"module_path"`
+	ModuleVersion string            `json:"module_version"` // "(devel)" for local builds
+	VCS           string            `json:"vcs"`            // e.g., "git" (empty if built without VCS stamping)
+	VCSRevision   string            `json:"vcs_revision"`
+	VCSTime       time.Time         `json:"vcs_time"`
+	VCSModified   bool              `json:"vcs_modified"` // Dirty working tree at build time
+	Settings      map[string]string `json:"settings"`     // GOOS, GOARCH, CGO_ENABLED, -tags, -ldflags, ...
+	Dependencies  []Dependency      `json:"dependencies"`
+}
+
+// Dependency is a module linked into the binary.
+type Dependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Sum     string `json:"sum,omitempty"`
+	Replace string `json:"replace,omitempty"` // "path version" of the replacement module
+}
+
+// ReadBuildInfo returns the build metadata of the running binary (nil if not built with module support).
+func ReadBuildInfo() *BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	build := &BuildInfo{
+		GoVersion:     info.GoVersion,
+		ModulePath:    info.Main.Path,
+		ModuleVersion: info.Main.Version,
+		Settings:      make(map[string]string),
+		Dependencies:  make([]Dependency, 0, len(info.Deps)),
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs":
+			build.VCS = setting.Value
+		case "vcs.revision":
+			build.VCSRevision = setting.Value
+		case "vcs.time":
+			build.VCSTime, _ = time.Parse(time.RFC3339, setting.Value)
+		case "vcs.modified":
+			build.VCSModified = setting.Value == "true"
+		default:
+			build.Settings[setting.Key] = setting.Value
+		}
+	}
+
+	for _, dep := range info.Deps {
+		dependency := Dependency{Path: dep.Path, Version: dep.Version, Sum: dep.Sum}
+		if dep.Replace != nil {
+			dependency.Replace = dep.Replace.Path + " " + dep.Replace.Version
+		}
+		build.Dependencies = append(build.Dependencies, dependency)
+	}
+	sort.Slice(build.Dependencies, func(i, j int) bool {
+		return build.Dependencies[i].Path < build.Dependencies[j].Path
+	})
+
+	return build
+}
+
+// BuildVersion derives a version from build metadata: module version if released,
+// otherwise the short VCS revision (+"-dirty"). Empty if neither is available.
+func BuildVersion() string {
+	build := ReadBuildInfo()
+	if build == nil {
+		return ""
+	}
+	if build.ModuleVersion != "" && build.ModuleVersion != "(devel)" {
+		return build.ModuleVersion
+	}
+	if build.VCSRevision == "" {
+		return ""
+	}
+
+	version := build.VCSRevision
+	if len(version) > 12 {
+		version = version[:12]
+	}
+	if build.VCSModified {
+		version += "-dirty"
+	}
+	return version
+}
+
+// data converts BuildInfo to component data.
+func (b *BuildInfo) data() map[string]interface{} {
+	vcsTime := ""
+	if !b.VCSTime.IsZero() {
+		vcsTime = b.VCSTime.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"go_version":     b.GoVersion,
+		"module_path":    b.ModulePath,
+		"module_version": b.ModuleVersion,
+		"vcs":            b.VCS,
+		"vcs_revision":   b.VCSRevision,
+		"vcs_time":       vcsTime,
+		"vcs_modified":   b.VCSModified,
+		"settings":       b.Settings,
+		"dependencies":   b.Dependencies,
+	}
+}
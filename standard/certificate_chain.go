@@ -0,0 +1,179 @@
+package standard
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Chain status values (reported as "chain_status" per certificate file).
+const (
+	ChainStatusValid      = "valid"        // Links intact and leaf verifies against the configured CA
+	ChainStatusUnverified = "unverified"   // Links intact, no CA configured to verify against
+	ChainStatusUntrusted  = "untrusted"    // Links intact, but leaf does not verify against the configured CA
+	ChainStatusOutOfOrder = "out_of_order" // All links present, but not in leaf → root order
+	ChainStatusBroken     = "broken"       // At least one certificate's issuer is missing or the signature is invalid
+)
+
+// ChainCertificate describes a single certificate within a PEM file (position 0 = first block).
+type ChainCertificate struct {
+	Position        int       `json:"position"`
+	Subject         string    `json:"subject"`
+	Issuer          string    `json:"issuer"`
+	ValidFrom       time.Time `json:"valid_from"`
+	ValidUntil      time.Time `json:"valid_until"`
+	DaysUntilExpiry int       `json:"days_until_expiry"`
+	IsExpired       bool      `json:"is_expired"`
+	IsCA            bool      `json:"is_ca"`
+	SelfSigned      bool      `json:"self_signed"`
+}
+
+// decodeCertificates parses every CERTIFICATE block of a PEM file (non-certificate blocks are skipped).
+func decodeCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate #%d: %w", len(certs), err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	return certs, nil
+}
+
+// buildChainInfo converts parsed certificates into per-position chain entries.
+func buildChainInfo(certs []*x509.Certificate) []ChainCertificate {
+	chain := make([]ChainCertificate, 0, len(certs))
+	for i, cert := range certs {
+		chain = append(chain, ChainCertificate{
+			Position:        i,
+			Subject:         cert.Subject.String(),
+			Issuer:          cert.Issuer.String(),
+			ValidFrom:       cert.NotBefore,
+			ValidUntil:      cert.NotAfter,
+			DaysUntilExpiry: int(time.Until(cert.NotAfter).Hours() / 24),
+			IsExpired:       time.Now().After(cert.NotAfter),
+			IsCA:            cert.IsCA,
+			SelfSigned:      isSelfSigned(cert),
+		})
+	}
+	return chain
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key (CheckSignatureFrom
+// would reject self-signed leaf certificates, which are not CAs).
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// allSelfSigned reports whether a file is a bundle of roots (no chain to check).
+func allSelfSigned(certs []*x509.Certificate) bool {
+	for _, cert := range certs {
+		if !isSelfSigned(cert) {
+			return false
+		}
+	}
+	return true
+}
+
+// issuedBy reports whether child was issued (name + signature) by parent.
+func issuedBy(child, parent *x509.Certificate) bool {
+	return bytes.Equal(child.RawIssuer, parent.RawSubject) && child.CheckSignatureFrom(parent) == nil
+}
+
+// analyzeChain checks the links inside a file and verifies the leaf against the configured CA.
+// caCerts may be empty (no CA configured). Returns chain status plus human-readable problems.
+func analyzeChain(certs []*x509.Certificate, caCerts []*x509.Certificate) (string, []string) {
+	var problems []string
+
+	// 1. Links in file order: certs[i] must be issued by certs[i+1] (root bundles have no links)
+	ordered := true
+	if !allSelfSigned(certs) {
+		for i := 0; i < len(certs)-1; i++ {
+			if !issuedBy(certs[i], certs[i+1]) {
+				ordered = false
+				problems = append(problems, fmt.Sprintf("position %d (%s) is not issued by position %d (%s)",
+					i, certs[i].Subject.String(), i+1, certs[i+1].Subject.String()))
+			}
+		}
+	}
+
+	if !ordered {
+		// 2. Out of order or broken? Walk issuers from the leaf: every cert must be reachable
+		visited := map[int]bool{0: true}
+		current := certs[0]
+		for !isSelfSigned(current) {
+			next := -1
+			for j, candidate := range certs {
+				if !visited[j] && issuedBy(current, candidate) {
+					next = j
+					break
+				}
+			}
+			if next < 0 {
+				break
+			}
+			visited[next] = true
+			current = certs[next]
+		}
+		if len(visited) != len(certs) {
+			return ChainStatusBroken, problems
+		}
+		return ChainStatusOutOfOrder, problems
+	}
+
+	// 3. Trust: verify the leaf against the configured CA (self-signed = root, others = intermediate)
+	if len(caCerts) == 0 {
+		return ChainStatusUnverified, problems
+	}
+
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, cert := range caCerts {
+		if isSelfSigned(cert) {
+			roots.AddCert(cert)
+		} else {
+			intermediates.AddCert(cert)
+		}
+	}
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		problems = append(problems, fmt.Sprintf("leaf does not verify against CA: %v", err))
+		return ChainStatusUntrusted, problems
+	}
+
+	return ChainStatusValid, problems
+}
+
+// loadCACertificates reads all certificates from the configured CA file.
+func loadCACertificates(caPath string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	return decodeCertificates(data)
+}
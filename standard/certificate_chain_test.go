@@ -0,0 +1,167 @@
+package standard
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testCert is a generated certificate with its key.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert creates a certificate for cn, signed by issuer (nil = self-signed).
+func newTestCert(t *testing.T, cn string, isCA bool, issuer *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		DNSNames:              []string{cn},
+	}
+	parent, signer := template, key
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// certsOf returns the certificates of test certs in the given order.
+func certsOf(tcs ...*testCert) []*x509.Certificate {
+	certs := make([]*x509.Certificate, 0, len(tcs))
+	for _, tc := range tcs {
+		certs = append(certs, tc.cert)
+	}
+	return certs
+}
+
+func TestAnalyzeChain(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	intermediate := newTestCert(t, "Test Intermediate", true, root)
+	leaf := newTestCert(t, "svc.example.internal", false, intermediate)
+	otherRoot := newTestCert(t, "Other Root", true, nil)
+	selfSigned := newTestCert(t, "self.example.internal", false, nil)
+
+	// Same subject as the intermediate but a different key: names link, signatures do not
+	impostor := newTestCert(t, "Test Intermediate", true, root)
+
+	tests := []struct {
+		name         string
+		certs        []*x509.Certificate
+		ca           []*x509.Certificate
+		want         string
+		wantProblems bool
+	}{
+		{name: "full chain in order", certs: certsOf(leaf, intermediate, root), ca: certsOf(root), want: ChainStatusValid},
+		{name: "leaf and intermediate", certs: certsOf(leaf, intermediate), ca: certsOf(root), want: ChainStatusValid},
+		{name: "intermediate from CA file", certs: certsOf(leaf), ca: certsOf(intermediate, root), want: ChainStatusValid},
+		{name: "no CA configured", certs: certsOf(leaf, intermediate), want: ChainStatusUnverified},
+		{name: "wrong CA", certs: certsOf(leaf, intermediate), ca: certsOf(otherRoot), want: ChainStatusUntrusted, wantProblems: true},
+		{name: "missing intermediate", certs: certsOf(leaf), ca: certsOf(root), want: ChainStatusUntrusted, wantProblems: true},
+		{name: "out of order", certs: certsOf(leaf, root, intermediate), ca: certsOf(root), want: ChainStatusOutOfOrder, wantProblems: true},
+		{name: "intermediate missing in file", certs: certsOf(leaf, root), ca: certsOf(root), want: ChainStatusBroken, wantProblems: true},
+		{name: "signature mismatch", certs: certsOf(leaf, impostor, root), ca: certsOf(root), want: ChainStatusBroken, wantProblems: true},
+		{name: "self-signed without CA", certs: certsOf(selfSigned), want: ChainStatusUnverified},
+		{name: "self-signed trusted by itself", certs: certsOf(selfSigned), ca: certsOf(selfSigned), want: ChainStatusValid},
+		{name: "self-signed against other CA", certs: certsOf(selfSigned), ca: certsOf(root), want: ChainStatusUntrusted, wantProblems: true},
+		{name: "root bundle", certs: certsOf(root, otherRoot), want: ChainStatusUnverified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, problems := analyzeChain(tt.certs, tt.ca)
+			if status != tt.want {
+				t.Errorf("status = %s, want %s (problems: %q)", status, tt.want, problems)
+			}
+			if (len(problems) > 0) != tt.wantProblems {
+				t.Errorf("problems = %q, want problems: %v", problems, tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestBuildChainInfo(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	leaf := newTestCert(t, "svc.example.internal", false, root)
+
+	chain := buildChainInfo(certsOf(leaf, root))
+	if len(chain) != 2 {
+		t.Fatalf("len(chain) = %d, want 2", len(chain))
+	}
+	if chain[0].Position != 0 || chain[0].IsCA || chain[0].SelfSigned {
+		t.Errorf("leaf entry = %+v", chain[0])
+	}
+	if chain[1].Position != 1 || !chain[1].IsCA || !chain[1].SelfSigned {
+		t.Errorf("root entry = %+v", chain[1])
+	}
+	if selfSigned := buildChainInfo(certsOf(newTestCert(t, "dev.example.internal", false, nil))); !selfSigned[0].SelfSigned {
+		t.Errorf("self-signed leaf entry = %+v", selfSigned[0])
+	}
+	if chain[0].DaysUntilExpiry < 88 || chain[0].DaysUntilExpiry > 90 || chain[0].IsExpired {
+		t.Errorf("leaf expiry = %d days (expired: %v)", chain[0].DaysUntilExpiry, chain[0].IsExpired)
+	}
+}
+
+func TestDecodeCertificates(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	leaf := newTestCert(t, "svc.example.internal", false, root)
+	certPEM := func(tc *testCert) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.cert.Raw})
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("not parsed")})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr bool
+	}{
+		{name: "single", data: certPEM(leaf), want: 1},
+		{name: "chain", data: append(certPEM(leaf), certPEM(root)...), want: 2},
+		{name: "key blocks skipped", data: append(append(keyPEM, certPEM(leaf)...), certPEM(root)...), want: 2},
+		{name: "key only", data: keyPEM, wantErr: true},
+		{name: "empty", data: nil, wantErr: true},
+		{name: "corrupt certificate", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0x30, 0x01}}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certs, err := decodeCertificates(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(certs) != tt.want {
+				t.Errorf("got %d certificates, want %d", len(certs), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package standard
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// CertificateDiscovery configures which files CertificateMonitor scans.
+// Zero value of each field falls back to the classic behaviour (CertDir/*.cert.pem).
+type CertificateDiscovery struct {
+	Roots          []string      // Directories to scan (empty = CertDir)
+	Patterns       []string      // Globs relative to each root, "**" matches any depth (empty = "*.cert.pem")
+	PurposeRules   []PurposeRule // First matching rule wins (no match = filename heuristic)
+	PKCS12Password string        // Password for *.p12/*.pfx bundles (often empty)
+}
+
+// PurposeRule maps files matching Pattern (relative to their root, "**" supported) to a purpose.
+type PurposeRule struct {
+	Pattern string // e.g., "clients/**", "*-to-*.crt"
+	Purpose string // e.g., "client", "server", "ca", "ca-chain"
+}
+
+// Validate checks all patterns for syntax errors.
+func (d CertificateDiscovery) Validate() error {
+	for _, pattern := range d.Patterns {
+		if err := validatePattern(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	for _, rule := range d.PurposeRules {
+		if rule.Purpose == "" {
+			return fmt.Errorf("purpose rule %q: Purpose required", rule.Pattern)
+		}
+		if err := validatePattern(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid purpose rule pattern %q: %w", rule.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// discoveredFile is a certificate file found during discovery.
+type discoveredFile struct {
+	path string // Absolute or root-joined path
+	rel  string // Path relative to its root (slash-separated)
+	name string // Key in the certificates component
+}
+
+// SetDiscovery replaces the discovery configuration (roots, patterns, purpose rules, PKCS#12 password).
+func (cm *CertificateMonitor) SetDiscovery(discovery CertificateDiscovery) error {
+	if err := discovery.Validate(); err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.discovery = discovery
+	return nil
+}
+
+// discover lists all certificate files of all roots (caller holds cm.mu).
+func (cm *CertificateMonitor) discover() ([]discoveredFile, map[string]error) {
+	return discoverFiles(cm.certDir, cm.discovery)
+}
+
+// discoverFiles lists all certificate files of all roots (empty Roots = certDir).
+// A failing root (or subdirectory) does not hide the others: errors are returned per path.
+func discoverFiles(certDir string, discovery CertificateDiscovery) ([]discoveredFile, map[string]error) {
+	roots := discovery.Roots
+	if len(roots) == 0 {
+		roots = []string{certDir}
+	}
+	patterns := discovery.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"*.cert.pem"}
+	}
+
+	var files []discoveredFile
+	rootErrors := make(map[string]error)
+	seen := make(map[string]bool)
+	names := make(map[string]int)
+
+	for _, root := range roots {
+		// WalkDir does not follow a symlinked root (Kubernetes secret mounts, /etc/ssl links)
+		resolved, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			rootErrors[root] = err
+			continue
+		}
+
+		// Paths are reported under the configured root: stable when a symlink target is swapped
+		add := func(path string) error {
+			rel, err := filepath.Rel(resolved, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			path = filepath.Join(root, filepath.FromSlash(rel))
+			if seen[path] || isKeyFile(rel) {
+				return nil
+			}
+			seen[path] = true
+			files = append(files, discoveredFile{path: path, rel: rel, name: rel})
+			names[rel]++
+			return nil
+		}
+
+		// Plain patterns: Glob (follows symlinks, no walk of the whole subtree on every poll)
+		var recursive []string
+		for _, pattern := range patterns {
+			if isRecursivePattern(pattern) {
+				recursive = append(recursive, pattern)
+				continue
+			}
+			matches, err := filepath.Glob(filepath.Join(resolved, filepath.FromSlash(pattern)))
+			if err != nil {
+				rootErrors[root] = err
+				continue
+			}
+			for _, match := range matches {
+				if isRegularFile(match) {
+					if err := add(match); err != nil {
+						rootErrors[match] = err
+					}
+				}
+			}
+		}
+		if len(recursive) == 0 {
+			continue
+		}
+
+		err = filepath.WalkDir(resolved, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if path == resolved {
+					return err
+				}
+				// Unreadable subdirectory: report it, keep walking the rest of the root
+				rootErrors[path] = err
+				return nil
+			}
+			if entry.IsDir() {
+				// Kubernetes AtomicWriter data dirs (..2024_01_01...) - reached via the ..data links
+				if path != resolved && strings.HasPrefix(entry.Name(), "..") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.Type()&fs.ModeSymlink != 0 && !isRegularFile(path) {
+				return nil
+			}
+
+			rel, err := filepath.Rel(resolved, path)
+			if err != nil {
+				return err
+			}
+			for _, pattern := range recursive {
+				if matchPattern(pattern, filepath.ToSlash(rel)) {
+					return add(path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			rootErrors[root] = err
+		}
+	}
+
+	// Same relative name under several roots: disambiguate with the full path
+	for i := range files {
+		if names[files[i].rel] > 1 {
+			files[i].name = files[i].path
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, rootErrors
+}
+
+// isRecursivePattern reports whether a pattern contains a "**" segment.
+func isRecursivePattern(pattern string) bool {
+	return slices.Contains(strings.Split(pattern, "/"), "**")
+}
+
+// isRegularFile reports whether path (after following symlinks) is a regular file.
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isKeyFile reports whether a file is a private key by name (see keyPathCandidates).
+func isKeyFile(name string) bool {
+	return strings.HasSuffix(name, ".key.pem") || strings.HasSuffix(name, "-key.pem") || strings.HasSuffix(name, ".key")
+}
+
+// purposeFor applies purpose rules, falling back to the filename heuristic (caller holds cm.mu).
+func (cm *CertificateMonitor) purposeFor(file discoveredFile) string {
+	for _, rule := range cm.discovery.PurposeRules {
+		if matchPattern(rule.Pattern, file.rel) {
+			return rule.Purpose
+		}
+	}
+	return determinePurpose(filepath.Base(file.path))
+}
+
+// validatePattern checks every non-"**" segment with filepath.Match syntax.
+func validatePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchPattern matches a slash-separated relative path against a glob with "**" support.
+func matchPattern(pattern, rel string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchSegments matches path segments recursively ("**" = zero or more segments).
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
+
+// errNotCertificate marks PEM files without certificates (keys, CSRs, parameters) - skipped by scans.
+var errNotCertificate = errors.New("no certificate in PEM file")
+
+// pemWithoutCertificate reports whether data holds PEM blocks, none of them a certificate.
+func pemWithoutCertificate(data []byte) bool {
+	found := false
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return found
+		}
+		if block.Type == "CERTIFICATE" {
+			return false
+		}
+		found = true
+	}
+}
+
+// readCertificateFile reads certificates in PEM, DER or PKCS#12 format.
+// Returns the certificates (leaf first) and, for PKCS#12, the embedded private key.
+func readCertificateFile(path, pkcs12Password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	// PKCS#12 bundles (detected by extension - the format has no reliable magic)
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".p12" || ext == ".pfx" {
+		return decodePKCS12(data, pkcs12Password)
+	}
+
+	// PEM (text) - all blocks
+	if ext == ".pem" || bytes.Contains(data, []byte("-----BEGIN")) {
+		if pemWithoutCertificate(data) {
+			return nil, nil, errNotCertificate
+		}
+		certs, err := decodeCertificates(data)
+		return certs, nil, err
+	}
+
+	// DER (binary) - one or more concatenated certificates
+	certs, err := x509.ParseCertificates(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse DER certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("no certificate found")
+	}
+	return certs, nil, nil
+}
+
+// decodePKCS12 extracts all certificates and the private key of a PKCS#12 bundle (leaf first).
+// Bundles without a key (trust stores) return certificates only.
+func decodePKCS12(data []byte, password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	privateKey, leaf, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		certs, trustErr := pkcs12.DecodeTrustStore(data, password)
+		if trustErr != nil || len(certs) == 0 {
+			return nil, nil, fmt.Errorf("failed to decode PKCS#12: %w", err)
+		}
+		return orderChain(certs, 0), nil, nil
+	}
+
+	// CA bag order is arbitrary: leaf first, then its issuers
+	return orderChain(append([]*x509.Certificate{leaf}, caCerts...), 0), privateKey, nil
+}
+
+// orderChain returns certs starting at leaf, followed by its issuers, then any unrelated certificates.
+func orderChain(certs []*x509.Certificate, leaf int) []*x509.Certificate {
+	used := map[int]bool{leaf: true}
+	ordered := []*x509.Certificate{certs[leaf]}
+
+	current := certs[leaf]
+	for !isSelfSigned(current) {
+		next := -1
+		for i, candidate := range certs {
+			if !used[i] && issuedBy(current, candidate) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		used[next] = true
+		ordered = append(ordered, certs[next])
+		current = certs[next]
+	}
+
+	for i, cert := range certs {
+		if !used[i] {
+			ordered = append(ordered, cert)
+		}
+	}
+	return ordered
+}
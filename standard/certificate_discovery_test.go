@@ -0,0 +1,293 @@
+package standard
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"*.cert.pem", "svc.cert.pem", true},
+		{"*.cert.pem", "sub/svc.cert.pem", false},
+		{"*.cert.pem", "svc.key.pem", false},
+		{"**/*.crt", "svc.crt", true}, // "**" matches zero segments
+		{"**/*.crt", "a/b/svc.crt", true},
+		{"**/*.crt", "a/b/svc.pem", false},
+		{"clients/**", "clients/a/b.pem", true},
+		{"clients/**", "servers/a.pem", false},
+		{"a/**/b/*.pem", "a/b/x.pem", true},
+		{"a/**/b/*.pem", "a/x/y/b/z.pem", true},
+		{"a/**/b/*.pem", "a/x/z.pem", false},
+		{"**", "any/depth/file", true},
+		{"*-to-*.crt", "svc-to-db.crt", true},
+		{"*-to-*.crt", "svc.crt", false},
+		{"[ab].pem", "a.pem", true},
+		{"[ab].pem", "c.pem", false},
+		{"*", "sub/file", false}, // "*" does not cross segments
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.rel, func(t *testing.T) {
+			if got := matchPattern(tt.pattern, tt.rel); got != tt.want {
+				t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{"*.cert.pem", false},
+		{"**", false},
+		{"certs/**/*.pem", false},
+		{"[ab].pem", false},
+		{"", true},
+		{"[", true},
+		{"**/[a-", true},
+		{"certs/\\", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if err := validatePattern(tt.pattern); (err != nil) != tt.wantErr {
+				t.Errorf("validatePattern(%q) = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCertificateDiscoveryValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		discovery CertificateDiscovery
+		wantErr   bool
+	}{
+		{name: "zero value", discovery: CertificateDiscovery{}},
+		{name: "valid", discovery: CertificateDiscovery{Patterns: []string{"**/*.crt"}, PurposeRules: []PurposeRule{{Pattern: "clients/**", Purpose: "client"}}}},
+		{name: "invalid pattern", discovery: CertificateDiscovery{Patterns: []string{"["}}, wantErr: true},
+		{name: "invalid rule pattern", discovery: CertificateDiscovery{PurposeRules: []PurposeRule{{Pattern: "[", Purpose: "client"}}}, wantErr: true},
+		{name: "rule without purpose", discovery: CertificateDiscovery{PurposeRules: []PurposeRule{{Pattern: "*.pem"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.discovery.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrderChain(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	intermediate := newTestCert(t, "Test Intermediate", true, root)
+	leaf := newTestCert(t, "svc.example.internal", false, intermediate)
+	unrelated := newTestCert(t, "Other Root", true, nil)
+
+	tests := []struct {
+		name  string
+		certs []*x509.Certificate
+		leaf  int
+		want  []*x509.Certificate
+	}{
+		{name: "already ordered", certs: certsOf(leaf, intermediate, root), leaf: 0, want: certsOf(leaf, intermediate, root)},
+		{name: "reversed", certs: certsOf(root, intermediate, leaf), leaf: 2, want: certsOf(leaf, intermediate, root)},
+		{name: "shuffled with unrelated", certs: certsOf(unrelated, root, leaf, intermediate), leaf: 2, want: certsOf(leaf, intermediate, root, unrelated)},
+		{name: "missing intermediate", certs: certsOf(root, leaf), leaf: 1, want: certsOf(leaf, root)},
+		{name: "self-signed leaf", certs: certsOf(unrelated, root), leaf: 1, want: certsOf(root, unrelated)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderChain(tt.certs, tt.leaf)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderChain = %v, want %v", subjects(got), subjects(tt.want))
+			}
+		})
+	}
+}
+
+// subjects returns the common names of certs (readable test failures).
+func subjects(certs []*x509.Certificate) []string {
+	names := make([]string, 0, len(certs))
+	for _, cert := range certs {
+		names = append(names, cert.Subject.CommonName)
+	}
+	return names
+}
+
+func TestReadCertificateFile(t *testing.T) {
+	root := newTestCert(t, "Test Root", true, nil)
+	intermediate := newTestCert(t, "Test Intermediate", true, root)
+	leaf := newTestCert(t, "svc.example.internal", false, intermediate)
+
+	certPEM := func(tcs ...*testCert) []byte {
+		var data []byte
+		for _, tc := range tcs {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.cert.Raw})...)
+		}
+		return data
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(leaf.key)
+	bundle, err := pkcs12.Modern.Encode(leaf.key, leaf.cert, certsOf(root, intermediate), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustStore, err := pkcs12.Modern.EncodeTrustStore(certsOf(root), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		data     []byte
+		password string
+		want     []*x509.Certificate
+		wantKey  bool
+		wantErr  error // Expected error (errors.Is)
+		anyErr   bool  // Some error expected
+	}{
+		{name: "PEM chain", file: "svc.cert.pem", data: certPEM(leaf, intermediate), want: certsOf(leaf, intermediate)},
+		{name: "PEM with .crt extension", file: "svc.crt", data: certPEM(leaf), want: certsOf(leaf)},
+		{name: "DER", file: "svc.der", data: leaf.cert.Raw, want: certsOf(leaf)},
+		{name: "concatenated DER", file: "chain.cer", data: append(append([]byte{}, leaf.cert.Raw...), intermediate.cert.Raw...), want: certsOf(leaf, intermediate)},
+		{name: "PKCS#12 with key, CA bag reordered", file: "svc.p12", data: bundle, password: "secret", want: certsOf(leaf, intermediate, root), wantKey: true},
+		{name: "PKCS#12 trust store", file: "trust.pfx", data: trustStore, want: certsOf(root)},
+		{name: "PKCS#12 wrong password", file: "svc.p12", data: bundle, password: "wrong", anyErr: true},
+		{name: "PEM key only", file: "svc.pem", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), wantErr: errNotCertificate},
+		{name: "garbage DER", file: "svc.der", data: []byte{0x01, 0x02}, anyErr: true},
+		{name: "empty DER", file: "svc.der", data: nil, anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, tt.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			certs, key, err := readCertificateFile(path, tt.password)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			case err != nil:
+				t.Fatalf("readCertificateFile: %v", err)
+			}
+
+			if !reflect.DeepEqual(subjects(certs), subjects(tt.want)) {
+				t.Errorf("certificates = %v, want %v", subjects(certs), subjects(tt.want))
+			}
+			if (key != nil) != tt.wantKey {
+				t.Errorf("key = %T, wantKey %v", key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestDiscoverFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel string) {
+		path := filepath.Join(dir, "certs", filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rel := range []string{
+		"svc.cert.pem",
+		"svc.key.pem",
+		"ca.crt",
+		"clients/db.cert.pem",
+		"clients/db-key.pem",
+		"..2024_01_01_00_00_00.123/tls.cert.pem", // Kubernetes AtomicWriter data dir
+	} {
+		write(rel)
+	}
+	// Secret mount layout: ..data -> data dir, file -> ..data/file
+	certs := filepath.Join(dir, "certs")
+	if err := os.Symlink("..2024_01_01_00_00_00.123", filepath.Join(certs, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..data/tls.cert.pem", filepath.Join(certs, "tls.cert.pem")); err != nil {
+		t.Fatal(err)
+	}
+	// Symlinked root (e.g., /etc/ssl/service -> /var/lib/certs)
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(certs, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		certDir   string
+		discovery CertificateDiscovery
+		want      []string // Component names
+	}{
+		{name: "default pattern", certDir: certs, want: []string{"svc.cert.pem", "tls.cert.pem"}},
+		{name: "symlinked root", certDir: link, want: []string{"svc.cert.pem", "tls.cert.pem"}},
+		{
+			name:      "recursive skips keys and data dirs",
+			certDir:   certs,
+			discovery: CertificateDiscovery{Patterns: []string{"**/*.pem", "*.crt"}},
+			want:      []string{"ca.crt", "clients/db.cert.pem", "svc.cert.pem", "tls.cert.pem"},
+		},
+		{
+			name:      "recursive under symlinked root",
+			certDir:   link,
+			discovery: CertificateDiscovery{Patterns: []string{"clients/**"}},
+			want:      []string{"clients/db.cert.pem"},
+		},
+		{
+			name:      "same name under two roots",
+			discovery: CertificateDiscovery{Roots: []string{certs, link}},
+			want:      []string{filepath.Join(certs, "svc.cert.pem"), filepath.Join(certs, "tls.cert.pem"), filepath.Join(link, "svc.cert.pem"), filepath.Join(link, "tls.cert.pem")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, rootErrors := discoverFiles(tt.certDir, tt.discovery)
+			if len(rootErrors) > 0 {
+				t.Fatalf("root errors: %v", rootErrors)
+			}
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("names = %q, want %q", names, tt.want)
+			}
+		})
+	}
+
+	// A missing root is reported without hiding the others
+	files, rootErrors := discoverFiles("", CertificateDiscovery{Roots: []string{filepath.Join(dir, "missing"), certs}})
+	if len(files) != 2 || rootErrors[filepath.Join(dir, "missing")] == nil {
+		t.Errorf("missing root: %d files, errors %v", len(files), rootErrors)
+	}
+}
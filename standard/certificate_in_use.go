@@ -0,0 +1,171 @@
+package standard
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// TLSCertificateUse is the client certificate the transport actually loaded.
+// CertificateMonitor receives it (and peer chains) as a transport.TLSObserver.
+type TLSCertificateUse struct {
+	Path              string
+	FingerprintSHA256 string
+	LoadedAt          time.Time
+	DiskFingerprint   string // Fingerprint of the file at Path in the last scan ("" = unreadable)
+	DiskError         string
+
+	certs []*x509.Certificate
+}
+
+// PeerCertificateObservation is the server chain seen during TLS handshakes with one server name.
+type PeerCertificateObservation struct {
+	ServerName          string
+	FingerprintSHA256   string
+	PreviousFingerprint string // Set when the peer presented a different certificate before
+	FirstSeen           time.Time
+	LastSeen            time.Time
+	Handshakes          int
+
+	certs []*x509.Certificate
+}
+
+// ClientCertificateLoaded records the client certificate loaded by the transport.
+func (cm *CertificateMonitor) ClientCertificateLoaded(certPath string, chain []*x509.Certificate) {
+	if len(chain) == 0 {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cm.inUse = &TLSCertificateUse{
+		Path:              certPath,
+		FingerprintSHA256: fingerprintOf(chain[0]),
+		LoadedAt:          time.Now().UTC(),
+		certs:             chain,
+	}
+	cm.refreshInUseDisk()
+}
+
+// PeerCertificatesObserved records the server chain of a verified TLS handshake.
+func (cm *CertificateMonitor) PeerCertificatesObserved(serverName string, chain []*x509.Certificate) {
+	if len(chain) == 0 {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	now := time.Now().UTC()
+	fingerprint := fingerprintOf(chain[0])
+
+	peer := cm.peers[serverName]
+	if peer == nil {
+		peer = &PeerCertificateObservation{ServerName: serverName, FirstSeen: now}
+		cm.peers[serverName] = peer
+	} else if peer.FingerprintSHA256 != fingerprint {
+		// Peer rotated its certificate
+		peer.PreviousFingerprint = peer.FingerprintSHA256
+		peer.FirstSeen = now
+	}
+
+	peer.FingerprintSHA256 = fingerprint
+	peer.LastSeen = now
+	peer.Handshakes++
+	peer.certs = chain
+}
+
+// refreshInUseDisk re-reads the file behind the loaded client certificate (caller holds cm.mu).
+func (cm *CertificateMonitor) refreshInUseDisk() {
+	if cm.inUse == nil {
+		return
+	}
+
+	certs, _, err := readCertificateFile(cm.inUse.Path, "")
+	if err != nil {
+		cm.inUse.DiskFingerprint = ""
+		cm.inUse.DiskError = err.Error()
+		return
+	}
+	cm.inUse.DiskFingerprint = fingerprintOf(certs[0])
+	cm.inUse.DiskError = ""
+}
+
+// inUseData converts the loaded client certificate to component data (caller holds cm.mu).
+func (cm *CertificateMonitor) inUseData() interface{} {
+	if cm.inUse == nil {
+		return nil
+	}
+
+	warnings := []string{}
+	if cm.inUse.DiskError != "" {
+		warnings = append(warnings, fmt.Sprintf("certificate file is unreadable: %s", cm.inUse.DiskError))
+	} else if cm.inUse.DiskFingerprint != cm.inUse.FingerprintSHA256 {
+		warnings = append(warnings, "certificate on disk differs from the loaded certificate (restart required to use the rotated certificate)")
+	}
+	chain := buildChainInfo(cm.inUse.certs) // Rebuilt: days_until_expiry must be current
+	warnings = append(warnings, expiryWarnings(chain, cm.warningDays())...)
+
+	return map[string]interface{}{
+		"path":               cm.inUse.Path,
+		"fingerprint_sha256": cm.inUse.FingerprintSHA256,
+		"disk_fingerprint":   cm.inUse.DiskFingerprint,
+		"loaded_at":          cm.inUse.LoadedAt.Format(time.RFC3339),
+		"chain":              chainData(chain),
+		"warnings":           warnings,
+	}
+}
+
+// peerData converts observed server chains to component data (caller holds cm.mu).
+func (cm *CertificateMonitor) peerData() map[string]interface{} {
+	data := make(map[string]interface{}, len(cm.peers))
+	for serverName, peer := range cm.peers {
+		chain := buildChainInfo(peer.certs) // Rebuilt: days_until_expiry must be current
+		warnings := expiryWarnings(chain, cm.warningDays())
+
+		// The CA file may have rotated since the handshake was verified
+		if len(cm.caCerts) > 0 {
+			status, problems := analyzeChain(peer.certs, cm.caCerts)
+			if status != ChainStatusValid {
+				warnings = append(warnings, fmt.Sprintf("server chain does not verify against the current CA file (%s)", status))
+				warnings = append(warnings, problems...)
+			}
+		}
+		if peer.PreviousFingerprint != "" {
+			warnings = append(warnings, fmt.Sprintf("server certificate changed since %s", peer.FirstSeen.Format(time.RFC3339)))
+		}
+
+		data[serverName] = map[string]interface{}{
+			"server_name":          peer.ServerName,
+			"fingerprint_sha256":   peer.FingerprintSHA256,
+			"previous_fingerprint": peer.PreviousFingerprint,
+			"first_seen":           peer.FirstSeen.Format(time.RFC3339),
+			"last_seen":            peer.LastSeen.Format(time.RFC3339),
+			"handshakes":           peer.Handshakes,
+			"chain":                chainData(chain),
+			"warnings":             warnings,
+		}
+	}
+	return data
+}
+
+// expiryWarnings flags expired certificates and those within warningDays of expiry.
+func expiryWarnings(chain []ChainCertificate, warningDays int) []string {
+	warnings := []string{}
+	for _, entry := range chain {
+		if entry.IsExpired {
+			warnings = append(warnings, fmt.Sprintf("position %d (%s) is expired", entry.Position, entry.Subject))
+		} else if entry.DaysUntilExpiry <= warningDays {
+			warnings = append(warnings, fmt.Sprintf("position %d (%s) expires in %d days", entry.Position, entry.Subject, entry.DaysUntilExpiry))
+		}
+	}
+	return warnings
+}
+
+// fingerprintOf returns the SHA-256 fingerprint in the same format as CertificateInfo.
+func fingerprintOf(cert *x509.Certificate) string {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return formatHexColon(fingerprint[:])
+}
@@ -0,0 +1,153 @@
+package standard
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeyInfo describes the private key belonging to a certificate (*.cert.pem → *.key.pem, or embedded in PKCS#12).
+type KeyInfo struct {
+	Path                string   `json:"path"`
+	Algorithm           string   `json:"algorithm"` // "RSA", "ECDSA", "Ed25519" (empty if unparsable)
+	Size                int      `json:"size"`      // RSA modulus bits / curve bits
+	Curve               string   `json:"curve,omitempty"`
+	MatchesCertificate  bool     `json:"matches_certificate"`
+	Permissions         string   `json:"permissions"` // e.g., "0600"
+	InsecurePermissions bool     `json:"insecure_permissions"`
+	Warnings            []string `json:"warnings"`
+	Error               string   `json:"error,omitempty"` // Key could not be read or parsed
+}
+
+// keyPathCandidates returns possible sibling key paths for a certificate file, most specific first.
+func keyPathCandidates(certPath string) []string {
+	switch {
+	case strings.HasSuffix(certPath, ".cert.pem"):
+		return []string{strings.TrimSuffix(certPath, ".cert.pem") + ".key.pem"}
+	case strings.HasSuffix(certPath, ".crt"), strings.HasSuffix(certPath, ".cer"), strings.HasSuffix(certPath, ".der"):
+		return []string{strings.TrimSuffix(certPath, filepath.Ext(certPath)) + ".key"}
+	case strings.HasSuffix(certPath, ".pem"):
+		base := strings.TrimSuffix(certPath, ".pem")
+		return []string{base + "-key.pem", base + ".key"}
+	default:
+		return nil
+	}
+}
+
+// inspectSiblingKey checks the first existing sibling key file (nil if none - CA certificates usually have no key).
+func inspectSiblingKey(certPath string, certPublicKey crypto.PublicKey) *KeyInfo {
+	for _, keyPath := range keyPathCandidates(certPath) {
+		if _, err := os.Stat(keyPath); err == nil {
+			return inspectKeyFile(keyPath, certPublicKey)
+		}
+	}
+	return nil
+}
+
+// inspectKeyFile checks a private key file against the certificate's public key.
+func inspectKeyFile(keyPath string, certPublicKey crypto.PublicKey) *KeyInfo {
+	info, ok := newKeyInfo(keyPath)
+	if !ok {
+		return nil
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		info.Error = fmt.Sprintf("failed to read key: %v", err)
+		return info
+	}
+
+	privateKey, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+
+	checkPrivateKey(info, privateKey, certPublicKey)
+	return info
+}
+
+// inspectEmbeddedKey checks a key bundled with the certificate (PKCS#12) - permissions apply to the bundle.
+func inspectEmbeddedKey(bundlePath string, privateKey crypto.PrivateKey, certPublicKey crypto.PublicKey) *KeyInfo {
+	info, ok := newKeyInfo(bundlePath)
+	if !ok {
+		return nil
+	}
+	checkPrivateKey(info, privateKey, certPublicKey)
+	return info
+}
+
+// newKeyInfo creates KeyInfo with permission checks (false if the file does not exist).
+func newKeyInfo(keyPath string) (*KeyInfo, bool) {
+	stat, err := os.Stat(keyPath)
+	if err != nil {
+		return nil, false
+	}
+
+	perm := stat.Mode().Perm()
+	info := &KeyInfo{
+		Path:        keyPath,
+		Permissions: fmt.Sprintf("%04o", perm),
+		Warnings:    []string{},
+	}
+
+	// Private keys must not be accessible by group/others (same rule as ssh)
+	if perm&0o077 != 0 {
+		info.InsecurePermissions = true
+		info.Warnings = append(info.Warnings, fmt.Sprintf("key file permissions %04o allow group/other access (expected 0600 or 0400)", perm))
+	}
+	return info, true
+}
+
+// checkPrivateKey reports algorithm/size and verifies the key matches the certificate.
+func checkPrivateKey(info *KeyInfo, privateKey crypto.PrivateKey, certPublicKey crypto.PublicKey) {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		info.Error = fmt.Sprintf("unsupported private key type %T", privateKey)
+		return
+	}
+
+	info.Algorithm, info.Size = publicKeyDetails(signer.Public())
+	if pub, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		info.Curve = pub.Curve.Params().Name
+	}
+	if info.Algorithm == "RSA" && info.Size < 2048 {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("RSA key size %d is below 2048 bits", info.Size))
+	}
+
+	// Compare public keys (all stdlib public key types implement Equal)
+	if publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); ok {
+		info.MatchesCertificate = publicKey.Equal(certPublicKey)
+	}
+	if !info.MatchesCertificate {
+		info.Warnings = append(info.Warnings, "private key does not match certificate public key (rotated certificate without key?)")
+	}
+}
+
+// parsePrivateKey decodes the first private key block (PKCS#8, PKCS#1 or SEC 1).
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode private key PEM block")
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			return x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("encrypted private key (cannot verify without passphrase)")
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package standard
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInspectKeyFile(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherECKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	pkcs8 := func(key crypto.PrivateKey) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	sec1, _ := x509.MarshalECPrivateKey(ecKey)
+
+	tests := []struct {
+		name          string
+		keyPEM        []byte
+		perm          os.FileMode
+		certPublicKey crypto.PublicKey
+		wantAlgorithm string
+		wantSize      int
+		wantMatch     bool
+		wantInsecure  bool
+		wantWarnings  int
+		wantError     bool
+	}{
+		{
+			name:          "ECDSA SEC 1 matching",
+			keyPEM:        pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+			perm:          0o600,
+			certPublicKey: &ecKey.PublicKey,
+			wantAlgorithm: "ECDSA", wantSize: 256, wantMatch: true,
+		},
+		{
+			name:          "ECDSA PKCS#8 matching read-only",
+			keyPEM:        pkcs8(ecKey),
+			perm:          0o400,
+			certPublicKey: &ecKey.PublicKey,
+			wantAlgorithm: "ECDSA", wantSize: 256, wantMatch: true,
+		},
+		{
+			name:          "rotated certificate without key",
+			keyPEM:        pkcs8(ecKey),
+			perm:          0o600,
+			certPublicKey: &otherECKey.PublicKey,
+			wantAlgorithm: "ECDSA", wantSize: 256, wantWarnings: 1,
+		},
+		{
+			name:          "group readable",
+			keyPEM:        pkcs8(ecKey),
+			perm:          0o640,
+			certPublicKey: &ecKey.PublicKey,
+			wantAlgorithm: "ECDSA", wantSize: 256, wantMatch: true, wantInsecure: true, wantWarnings: 1,
+		},
+		{
+			name:          "RSA PKCS#1 below 2048 bits",
+			keyPEM:        pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			perm:          0o600,
+			certPublicKey: &rsaKey.PublicKey,
+			wantAlgorithm: "RSA", wantSize: 1024, wantMatch: true, wantWarnings: 1,
+		},
+		{
+			name:          "Ed25519 matching",
+			keyPEM:        pkcs8(edKey),
+			perm:          0o600,
+			certPublicKey: edKey.Public(),
+			wantAlgorithm: "Ed25519", wantSize: 256, wantMatch: true,
+		},
+		{
+			name:          "type mismatch",
+			keyPEM:        pkcs8(edKey),
+			perm:          0o600,
+			certPublicKey: &ecKey.PublicKey,
+			wantAlgorithm: "Ed25519", wantSize: 256, wantWarnings: 1,
+		},
+		{
+			name:          "encrypted",
+			keyPEM:        pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte{1}}),
+			perm:          0o600,
+			certPublicKey: &ecKey.PublicKey,
+			wantError:     true,
+		},
+		{
+			name:          "no key block",
+			keyPEM:        []byte("not a key"),
+			perm:          0o600,
+			certPublicKey: &ecKey.PublicKey,
+			wantError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyPath := filepath.Join(t.TempDir(), "svc.key.pem")
+			if err := os.WriteFile(keyPath, tt.keyPEM, tt.perm); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(keyPath, tt.perm); err != nil { // Not reduced by umask
+				t.Fatal(err)
+			}
+
+			info := inspectKeyFile(keyPath, tt.certPublicKey)
+			if info == nil {
+				t.Fatal("inspectKeyFile = nil")
+			}
+			if (info.Error != "") != tt.wantError {
+				t.Fatalf("Error = %q, wantError %v", info.Error, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if info.Algorithm != tt.wantAlgorithm || info.Size != tt.wantSize {
+				t.Errorf("key = %s/%d, want %s/%d", info.Algorithm, info.Size, tt.wantAlgorithm, tt.wantSize)
+			}
+			if info.MatchesCertificate != tt.wantMatch {
+				t.Errorf("MatchesCertificate = %v, want %v", info.MatchesCertificate, tt.wantMatch)
+			}
+			if info.InsecurePermissions != tt.wantInsecure {
+				t.Errorf("InsecurePermissions = %v, want %v (%s)", info.InsecurePermissions, tt.wantInsecure, info.Permissions)
+			}
+			if len(info.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %q, want %d", info.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestInspectKeyFileMissing(t *testing.T) {
+	if info := inspectKeyFile(filepath.Join(t.TempDir(), "missing.key.pem"), nil); info != nil {
+		t.Errorf("inspectKeyFile = %+v, want nil", info)
+	}
+}
+
+func TestKeyPathCandidates(t *testing.T) {
+	tests := []struct {
+		certPath string
+		want     []string
+	}{
+		{"/certs/svc.cert.pem", []string{"/certs/svc.key.pem"}},
+		{"/certs/svc.crt", []string{"/certs/svc.key"}},
+		{"/certs/svc.cer", []string{"/certs/svc.key"}},
+		{"/certs/svc.der", []string{"/certs/svc.key"}},
+		{"/certs/tls.pem", []string{"/certs/tls-key.pem", "/certs/tls.key"}},
+		{"/certs/bundle.p12", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.certPath, func(t *testing.T) {
+			if got := keyPathCandidates(tt.certPath); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keyPathCandidates = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspectSiblingKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.pem")
+	if info := inspectSiblingKey(certPath, &key.PublicKey); info != nil {
+		t.Fatalf("no key: inspectSiblingKey = %+v, want nil (CA certificates have no key)", info)
+	}
+
+	// Second candidate only
+	if err := os.WriteFile(filepath.Join(dir, "tls.key"), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	info := inspectSiblingKey(certPath, &key.PublicKey)
+	if info == nil || info.Path != filepath.Join(dir, "tls.key") || !info.MatchesCertificate {
+		t.Fatalf("inspectSiblingKey = %+v, want matching tls.key", info)
+	}
+
+	// First candidate wins
+	if err := os.WriteFile(filepath.Join(dir, "tls-key.pem"), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if info := inspectSiblingKey(certPath, &key.PublicKey); info == nil || info.Path != filepath.Join(dir, "tls-key.pem") {
+		t.Errorf("inspectSiblingKey = %+v, want tls-key.pem", info)
+	}
+}
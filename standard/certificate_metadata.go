@@ -0,0 +1,102 @@
+package standard
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// keyUsageNames maps x509.KeyUsage bits to RFC 5280 names.
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digital_signature"},
+	{x509.KeyUsageContentCommitment, "content_commitment"},
+	{x509.KeyUsageKeyEncipherment, "key_encipherment"},
+	{x509.KeyUsageDataEncipherment, "data_encipherment"},
+	{x509.KeyUsageKeyAgreement, "key_agreement"},
+	{x509.KeyUsageCertSign, "cert_sign"},
+	{x509.KeyUsageCRLSign, "crl_sign"},
+	{x509.KeyUsageEncipherOnly, "encipher_only"},
+	{x509.KeyUsageDecipherOnly, "decipher_only"},
+}
+
+// extKeyUsageNames maps x509.ExtKeyUsage values to names.
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                        "any",
+	x509.ExtKeyUsageServerAuth:                 "server_auth",
+	x509.ExtKeyUsageClientAuth:                 "client_auth",
+	x509.ExtKeyUsageCodeSigning:                "code_signing",
+	x509.ExtKeyUsageEmailProtection:            "email_protection",
+	x509.ExtKeyUsageIPSECEndSystem:             "ipsec_end_system",
+	x509.ExtKeyUsageIPSECTunnel:                "ipsec_tunnel",
+	x509.ExtKeyUsageIPSECUser:                  "ipsec_user",
+	x509.ExtKeyUsageTimeStamping:               "time_stamping",
+	x509.ExtKeyUsageOCSPSigning:                "ocsp_signing",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto: "microsoft_server_gated_crypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:  "netscape_server_gated_crypto",
+}
+
+// applyX509Metadata copies identity, key and revocation metadata from cert into info.
+func applyX509Metadata(info *CertificateInfo, cert *x509.Certificate) {
+	info.SerialNumber = formatHexColon(cert.SerialNumber.Bytes())
+	info.FingerprintSHA256 = fingerprintOf(cert)
+	info.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	info.PublicKeyAlgorithm, info.PublicKeySize = publicKeyDetails(cert.PublicKey)
+
+	info.URISANs = []string{}
+	for _, uri := range cert.URIs {
+		info.URISANs = append(info.URISANs, uri.String())
+	}
+	info.EmailSANs = append([]string{}, cert.EmailAddresses...)
+
+	info.KeyUsage = []string{}
+	for _, ku := range keyUsageNames {
+		if cert.KeyUsage&ku.usage != 0 {
+			info.KeyUsage = append(info.KeyUsage, ku.name)
+		}
+	}
+
+	info.ExtKeyUsage = []string{}
+	for _, eku := range cert.ExtKeyUsage {
+		name, ok := extKeyUsageNames[eku]
+		if !ok {
+			name = fmt.Sprintf("unknown(%d)", eku)
+		}
+		info.ExtKeyUsage = append(info.ExtKeyUsage, name)
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		info.ExtKeyUsage = append(info.ExtKeyUsage, oid.String())
+	}
+
+	info.OCSPServers = append([]string{}, cert.OCSPServer...)
+	info.CRLDistributionPoints = append([]string{}, cert.CRLDistributionPoints...)
+	info.IssuingCertificateURLs = append([]string{}, cert.IssuingCertificateURL...)
+}
+
+// publicKeyDetails returns algorithm name and size in bits (e.g., "RSA", 2048).
+func publicKeyDetails(publicKey interface{}) (string, int) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", pub.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return fmt.Sprintf("%T", publicKey), 0
+	}
+}
+
+// formatHexColon formats bytes as uppercase colon-separated hex (openssl style).
+func formatHexColon(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
@@ -2,29 +2,60 @@ package standard
 
 import (
 	"crypto/x509"
-	"encoding/pem"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
-
 )
 
 // CertificateMonitor tracks X.509 certificates for expiry and metadata
 type CertificateMonitor struct {
 	certDir   string
+	caPath    string // Optional: CA used to verify chains (empty = links only)
+	discovery CertificateDiscovery
 	mu        sync.RWMutex
 	certs     map[string]*CertificateInfo
 	lastScan  time.Time
 	scanError error
+	logs      *RecentLogs // Optional: Warn when a valid certificate becomes unparsable
+
+	// Per-file failures of the last scan (name -> error), see ScanError
+	scanErrors   map[string]*ScanError
+	scanDuration time.Duration
+	caCerts      []*x509.Certificate // CA of the last scan (verifies observed peer chains)
+
+	// Certificates actually used on the wire (see certificate_in_use.go)
+	inUse *TLSCertificateUse
+	peers map[string]*PeerCertificateObservation
+
+	// OCSP/CRL revocation checks (see certificate_revocation.go)
+	revocation          RevocationConfig
+	revocationResults   map[string]*RevocationResult // fingerprint -> last result
+	crlCache            map[string]*cachedCRL        // CRL URL -> downloaded list
+	lastRevocationCheck time.Time
+
+	// Event-driven rescans (see certificate_watcher.go)
+	triggerFunc     func() // Called on file changes and expiry-threshold crossings
+	thresholds      []int  // Days before expiry (empty = DefaultExpiryThresholds)
+	thresholdTimer  *time.Timer
+	lastFingerprint string
+	running         bool
+	stopChan        chan struct{}
+	watchers        sync.WaitGroup // Watch loop and threshold rescans (Stop waits)
+}
+
+// ScanError describes a file (or root/CA) that could not be scanned
+type ScanError struct {
+	Path         string    `json:"path"`
+	Error        string    `json:"error"`
+	FailingSince time.Time `json:"failing_since"` // First scan that failed (kept while the failure persists)
 }
 
 // CertificateInfo holds parsed certificate metadata
 type CertificateInfo struct {
 	Path            string    `json:"path"`
-	Purpose         string    `json:"purpose"`           // "server", "client", "ca", "ca-chain"
+	Purpose         string    `json:"purpose"` // "server", "client", "ca", "ca-chain"
 	Subject         string    `json:"subject"`
 	Issuer          string    `json:"issuer"`
 	ValidFrom       time.Time `json:"valid_from"`
@@ -32,51 +63,155 @@ type CertificateInfo struct {
 	DaysUntilExpiry int       `json:"days_until_expiry"`
 	SANs            []string  `json:"sans"`
 	IsExpired       bool      `json:"is_expired"`
-	ExpiryWarning   bool      `json:"expiry_warning"` // true if < 30 days
+	ExpiryWarning   bool      `json:"expiry_warning"` // true within the largest expiry threshold (default 30 days)
+
+	// Identity and key metadata (see certificate_metadata.go)
+	SerialNumber       string   `json:"serial_number"`      // Hex, colon-separated
+	FingerprintSHA256  string   `json:"fingerprint_sha256"` // Hex, colon-separated
+	SignatureAlgorithm string   `json:"signature_algorithm"`
+	PublicKeyAlgorithm string   `json:"public_key_algorithm"` // "RSA", "ECDSA", "Ed25519"
+	PublicKeySize      int      `json:"public_key_size"`      // Bits
+	URISANs            []string `json:"uri_sans"`             // e.g., SPIFFE IDs
+	EmailSANs          []string `json:"email_sans"`
+	KeyUsage           []string `json:"key_usage"`
+	ExtKeyUsage        []string `json:"ext_key_usage"`
+
+	// Revocation endpoints
+	OCSPServers            []string `json:"ocsp_servers"`
+	CRLDistributionPoints  []string `json:"crl_distribution_points"`
+	IssuingCertificateURLs []string `json:"issuing_certificate_urls"`
+
+	// Chain analysis (every PEM block in the file, position 0 = leaf)
+	Chain                []ChainCertificate `json:"chain"`
+	ChainStatus          string             `json:"chain_status"` // see ChainStatus* constants
+	ChainProblems        []string           `json:"chain_problems"`
+	ChainDaysUntilExpiry int                `json:"chain_days_until_expiry"` // Earliest expiry in the chain
+
+	// Matching private key (*.key.pem sibling, nil if none)
+	Key *KeyInfo `json:"key"`
+
+	certs []*x509.Certificate // Parsed chain (leaf first) for revocation checks
 }
 
 // NewCertificateMonitor creates a new certificate monitor for the given directory
 func NewCertificateMonitor(certDir string) *CertificateMonitor {
 	return &CertificateMonitor{
-		certDir: certDir,
-		certs:   make(map[string]*CertificateInfo),
+		certDir:    certDir,
+		certs:      make(map[string]*CertificateInfo),
+		scanErrors: make(map[string]*ScanError),
+		peers:      make(map[string]*PeerCertificateObservation),
+
+		revocationResults: make(map[string]*RevocationResult),
+		crlCache:          make(map[string]*cachedCRL),
 	}
 }
 
-// Scan discovers and parses all *.cert.pem files in the certificate directory
-func (cm *CertificateMonitor) Scan() error {
+// SetLogs sets the logs component used to warn about certificates that became unparsable.
+func (cm *CertificateMonitor) SetLogs(logs *RecentLogs) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.logs = logs
+}
+
+// SetCAPath sets the CA file used to verify certificate chains (e.g., Config.CAPath).
+func (cm *CertificateMonitor) SetCAPath(caPath string) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
+	cm.caPath = caPath
+}
+
+// Scan discovers and parses all certificate files (default: *.cert.pem in the certificate directory).
+// Files that fail to parse are reported in the component data; only a scan that found nothing at all
+// because every root failed returns an error.
+func (cm *CertificateMonitor) Scan() error {
+	cm.mu.Lock()
+	regressions, err := cm.scanLocked()
+	logs := cm.logs
+	cm.mu.Unlock()
+
+	// Log after releasing the lock
+	if logs != nil {
+		for _, scanErr := range regressions {
+			logs.Warn("Certificate became unparsable", map[string]interface{}{
+				"path":  scanErr.Path,
+				"error": scanErr.Error,
+			})
+		}
+	}
+	return err
+}
+
+// scanLocked performs the scan (caller holds cm.mu). Returns errors of previously valid certificates.
+func (cm *CertificateMonitor) scanLocked() ([]*ScanError, error) {
+	defer cm.scheduleThresholdTimer() // Certificates may have changed - re-arm threshold timer
+
+	startTime := time.Now()
+	previousCerts := cm.certs
+	previousErrors := cm.scanErrors
 
 	cm.certs = make(map[string]*CertificateInfo)
-	cm.lastScan = time.Now()
+	cm.scanErrors = make(map[string]*ScanError)
+	cm.scanError = nil
+	cm.lastScan = startTime
+	defer func() { cm.scanDuration = time.Since(startTime) }()
 
-	// Find all *.cert.pem files
-	pattern := filepath.Join(cm.certDir, "*.cert.pem")
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		cm.scanError = fmt.Errorf("failed to scan certificate directory: %w", err)
-		return cm.scanError
+	// recordError keeps FailingSince of failures that persist across scans
+	recordError := func(name, path string, err error) *ScanError {
+		scanErr := &ScanError{Path: path, Error: err.Error(), FailingSince: startTime}
+		if previous := previousErrors[name]; previous != nil {
+			scanErr.FailingSince = previous.FailingSince
+		}
+		cm.scanErrors[name] = scanErr
+		return scanErr
+	}
+
+	// Find all certificate files (default: CertDir/*.cert.pem, see CertificateDiscovery)
+	files, rootErrors := cm.discover()
+	for root, err := range rootErrors {
+		recordError(root, root, fmt.Errorf("failed to scan certificate directory: %w", err))
 	}
+	if len(files) == 0 && len(rootErrors) > 0 {
+		cm.scanError = fmt.Errorf("failed to scan certificate directories (%d failed)", len(rootErrors))
+	}
+
+	// Load CA for chain verification (re-read on every scan: the CA may rotate too)
+	var caCerts []*x509.Certificate
+	if cm.caPath != "" {
+		var err error
+		caCerts, err = loadCACertificates(cm.caPath)
+		if err != nil {
+			// Continue without verification - chains are reported as "unverified"
+			recordError(cm.caPath, cm.caPath, err)
+		}
+	}
+	cm.caCerts = caCerts
+
+	// Loaded client certificate vs. file on disk (rotation without restart)
+	cm.refreshInUseDisk()
 
 	// Parse each certificate
-	for _, path := range matches {
-		certInfo, err := parseCertificateFile(path)
+	var regressions []*ScanError
+	for _, file := range files {
+		certInfo, err := parseCertificateFile(file.path, caCerts, cm.discovery.PKCS12Password, cm.warningDays())
+		if errors.Is(err, errNotCertificate) {
+			continue // Key or other PEM file matched by a broad pattern
+		}
 		if err != nil {
-			// Log warning but continue with other certs
-			cm.scanError = fmt.Errorf("failed to parse %s: %w", path, err)
+			// Report the file but continue with other certs
+			scanErr := recordError(file.name, file.path, err)
+			if previousCerts[file.name] != nil {
+				regressions = append(regressions, scanErr)
+			}
 			continue
 		}
 
-		// Determine purpose from filename
-		filename := filepath.Base(path)
-		certInfo.Purpose = determinePurpose(filename)
+		// Determine purpose (explicit rules first, then filename heuristic)
+		certInfo.Purpose = cm.purposeFor(file)
 
-		cm.certs[filename] = certInfo
+		cm.certs[file.name] = certInfo
 	}
 
-	cm.scanError = nil
-	return nil
+	return regressions, cm.scanError
 }
 
 // ToComponent converts the certificate monitor state to an introspection component
@@ -84,79 +219,180 @@ func (cm *CertificateMonitor) GetData() interface{} {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	// Convert map to component data
+	// Convert map to component data (files are keyed by name relative to their root)
 	certData := make(map[string]interface{})
 	for filename, info := range cm.certs {
+		revocationStatus, revocationCheckedAt, revocation := cm.revocationData(info)
 		certData[filename] = map[string]interface{}{
-			"path":              info.Path,
-			"purpose":           info.Purpose,
-			"subject":           info.Subject,
-			"issuer":            info.Issuer,
-			"valid_from":        info.ValidFrom.Format(time.RFC3339),
-			"valid_until":       info.ValidUntil.Format(time.RFC3339),
-			"days_until_expiry": info.DaysUntilExpiry,
-			"sans":              info.SANs,
-			"is_expired":        info.IsExpired,
-			"expiry_warning":    info.ExpiryWarning,
+			"path":                     info.Path,
+			"purpose":                  info.Purpose,
+			"subject":                  info.Subject,
+			"issuer":                   info.Issuer,
+			"valid_from":               info.ValidFrom.Format(time.RFC3339),
+			"valid_until":              info.ValidUntil.Format(time.RFC3339),
+			"days_until_expiry":        info.DaysUntilExpiry,
+			"sans":                     info.SANs,
+			"is_expired":               info.IsExpired,
+			"expiry_warning":           info.ExpiryWarning,
+			"serial_number":            info.SerialNumber,
+			"fingerprint_sha256":       info.FingerprintSHA256,
+			"signature_algorithm":      info.SignatureAlgorithm,
+			"public_key_algorithm":     info.PublicKeyAlgorithm,
+			"public_key_size":          info.PublicKeySize,
+			"uri_sans":                 info.URISANs,
+			"email_sans":               info.EmailSANs,
+			"key_usage":                info.KeyUsage,
+			"ext_key_usage":            info.ExtKeyUsage,
+			"ocsp_servers":             info.OCSPServers,
+			"crl_distribution_points":  info.CRLDistributionPoints,
+			"issuing_certificate_urls": info.IssuingCertificateURLs,
+			"chain":                    chainData(info.Chain),
+			"chain_status":             info.ChainStatus,
+			"chain_problems":           info.ChainProblems,
+			"chain_days_until_expiry":  info.ChainDaysUntilExpiry,
+			"key":                      info.Key,
+			"revocation_status":        revocationStatus,
+			"revocation_checked_at":    revocationCheckedAt,
+			"revocation":               revocation,
+		}
+	}
+
+	scanErrors := make(map[string]interface{}, len(cm.scanErrors))
+	for name, scanErr := range cm.scanErrors {
+		scanErrors[name] = map[string]interface{}{
+			"path":          scanErr.Path,
+			"error":         scanErr.Error,
+			"failing_since": scanErr.FailingSince.Format(time.RFC3339),
 		}
 	}
 
+	lastScan := map[string]interface{}{
+		"at":          cm.lastScan.UTC().Format(time.RFC3339),
+		"duration_ms": cm.scanDuration.Milliseconds(),
+		"files_ok":    len(cm.certs),
+		"files_error": len(cm.scanErrors),
+	}
+	if cm.scanError != nil {
+		lastScan["error"] = cm.scanError.Error()
+	}
+
+	// Per-file entries stay at the top level (filename -> info); scan and transport data are siblings
+	certData["scan_errors"] = scanErrors
+	certData["last_scan"] = lastScan
+	certData["in_use"] = cm.inUseData()
+	certData["peer"] = cm.peerData()
 	return certData
 }
 
+// GetScanErrors returns the files that failed to parse in the last scan
+func (cm *CertificateMonitor) GetScanErrors() []*ScanError {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	errs := make([]*ScanError, 0, len(cm.scanErrors))
+	for _, scanErr := range cm.scanErrors {
+		errs = append(errs, scanErr)
+	}
+	return errs
+}
+
 // GetExpiringCertificates returns certificates expiring within the given number of days
+// (an expiring intermediate in the chain counts - it breaks the leaf just the same)
 func (cm *CertificateMonitor) GetExpiringCertificates(withinDays int) []*CertificateInfo {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
 	var expiring []*CertificateInfo
 	for _, cert := range cm.certs {
-		if cert.DaysUntilExpiry <= withinDays && !cert.IsExpired {
+		if cert.ChainDaysUntilExpiry <= withinDays && !chainExpired(cert) {
 			expiring = append(expiring, cert)
 		}
 	}
 	return expiring
 }
 
-// GetExpiredCertificates returns all expired certificates
+// GetExpiredCertificates returns all certificates with an expired certificate in their chain
 func (cm *CertificateMonitor) GetExpiredCertificates() []*CertificateInfo {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
 	var expired []*CertificateInfo
 	for _, cert := range cm.certs {
-		if cert.IsExpired {
+		if chainExpired(cert) {
 			expired = append(expired, cert)
 		}
 	}
 	return expired
 }
 
-// parseCertificateFile reads and parses a PEM-encoded certificate file
-func parseCertificateFile(path string) (*CertificateInfo, error) {
-	// Read certificate file
-	certPEM, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read certificate: %w", err)
+// GetKeyProblems returns certificates whose private key is mismatched, unparsable or insecurely stored
+func (cm *CertificateMonitor) GetKeyProblems() []*CertificateInfo {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var problems []*CertificateInfo
+	for _, cert := range cm.certs {
+		if cert.Key != nil && (!cert.Key.MatchesCertificate || cert.Key.InsecurePermissions || cert.Key.Error != "") {
+			problems = append(problems, cert)
+		}
+	}
+	return problems
+}
+
+// chainExpired reports whether any certificate in the file's chain is expired
+func chainExpired(info *CertificateInfo) bool {
+	for _, entry := range info.Chain {
+		if entry.IsExpired {
+			return true
+		}
 	}
+	return info.IsExpired
+}
 
-	// Decode first PEM block (for ca-chain, this will be the first cert)
-	block, _ := pem.Decode(certPEM)
-	if block == nil {
-		return nil, fmt.Errorf("failed to decode PEM block")
+// chainData converts chain entries to component data
+func chainData(chain []ChainCertificate) []map[string]interface{} {
+	data := make([]map[string]interface{}, 0, len(chain))
+	for _, entry := range chain {
+		data = append(data, map[string]interface{}{
+			"position":          entry.Position,
+			"subject":           entry.Subject,
+			"issuer":            entry.Issuer,
+			"valid_from":        entry.ValidFrom.Format(time.RFC3339),
+			"valid_until":       entry.ValidUntil.Format(time.RFC3339),
+			"days_until_expiry": entry.DaysUntilExpiry,
+			"is_expired":        entry.IsExpired,
+			"is_ca":             entry.IsCA,
+			"self_signed":       entry.SelfSigned,
+		})
 	}
+	return data
+}
 
-	// Parse certificate
-	cert, err := x509.ParseCertificate(block.Bytes)
+// parseCertificateFile reads and parses a certificate file (PEM with all blocks, DER or PKCS#12)
+// expiry_warning is set within warningDays of expiry (see warningDays).
+func parseCertificateFile(path string, caCerts []*x509.Certificate, pkcs12Password string, warningDays int) (*CertificateInfo, error) {
+	// Read ALL certificates (ca-chain and bundles contain intermediates + root)
+	certs, embeddedKey, err := readCertificateFile(path, pkcs12Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		return nil, err
+	}
+
+	// Leaf = first certificate (top-level fields describe it)
+	cert := certs[0]
+
+	// Private key: embedded (PKCS#12) or sibling file (*.cert.pem → *.key.pem)
+	var keyInfo *KeyInfo
+	if embeddedKey != nil {
+		keyInfo = inspectEmbeddedKey(path, embeddedKey, cert.PublicKey)
+	} else {
+		keyInfo = inspectSiblingKey(path, cert.PublicKey)
 	}
 
 	// Calculate expiry information
 	now := time.Now()
 	daysUntilExpiry := int(time.Until(cert.NotAfter).Hours() / 24)
 	isExpired := now.After(cert.NotAfter)
-	expiryWarning := daysUntilExpiry <= 30 && !isExpired
+	expiryWarning := daysUntilExpiry <= warningDays && !isExpired
 
 	// Collect SANs (Subject Alternative Names)
 	var sans []string
@@ -167,7 +403,20 @@ func parseCertificateFile(path string) (*CertificateInfo, error) {
 		sans = append(sans, fmt.Sprintf("IP:%s", ip.String()))
 	}
 
-	return &CertificateInfo{
+	// Chain analysis
+	chain := buildChainInfo(certs)
+	chainStatus, chainProblems := analyzeChain(certs, caCerts)
+	chainDaysUntilExpiry := daysUntilExpiry
+	for _, entry := range chain {
+		if entry.DaysUntilExpiry < chainDaysUntilExpiry {
+			chainDaysUntilExpiry = entry.DaysUntilExpiry
+		}
+	}
+	if chainProblems == nil {
+		chainProblems = []string{}
+	}
+
+	info := &CertificateInfo{
 		Path:            path,
 		Subject:         cert.Subject.String(),
 		Issuer:          cert.Issuer.String(),
@@ -177,7 +426,19 @@ func parseCertificateFile(path string) (*CertificateInfo, error) {
 		SANs:            sans,
 		IsExpired:       isExpired,
 		ExpiryWarning:   expiryWarning,
-	}, nil
+
+		Chain:                chain,
+		ChainStatus:          chainStatus,
+		ChainProblems:        chainProblems,
+		ChainDaysUntilExpiry: chainDaysUntilExpiry,
+
+		Key: keyInfo,
+
+		certs: certs,
+	}
+	applyX509Metadata(info, cert)
+
+	return info, nil
 }
 
 // determinePurpose infers certificate purpose from filename
@@ -0,0 +1,371 @@
+package standard
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// Revocation statuses reported per certificate file.
+const (
+	RevocationGood       = "good"        // OCSP responder or CRL confirms the certificate is not revoked
+	RevocationRevoked    = "revoked"     // Certificate is revoked
+	RevocationUnknown    = "unknown"     // OCSP responder does not know the certificate
+	RevocationError      = "error"       // Every endpoint failed (see revocation.error)
+	RevocationNotChecked = "not_checked" // Checking disabled, self-signed, or no OCSP/CRL endpoint
+)
+
+// Defaults for RevocationConfig.
+const (
+	defaultRevocationInterval = time.Hour
+	defaultRevocationTimeout  = 10 * time.Second
+	maxRevocationResponseSize = 10 << 20 // CRLs of large CAs can be several MB
+)
+
+// RevocationConfig enables OCSP/CRL revocation checks for monitored certificates.
+// Endpoints are taken from the certificates unless overridden (e.g., a local test responder).
+type RevocationConfig struct {
+	Enabled  bool
+	OCSPURL  string        // Optional: responder used for ALL certificates instead of their AIA OCSP URL
+	CRLURL   string        // Optional: CRL used for ALL certificates instead of their CRL distribution points
+	Interval time.Duration // Optional: re-check interval (0 = 1h; CRLs are also refreshed at NextUpdate)
+	Timeout  time.Duration // Optional: timeout per OCSP request / CRL download (0 = 10s)
+}
+
+// Validate checks override URLs and durations.
+func (r RevocationConfig) Validate() error {
+	for name, value := range map[string]string{"OCSPURL": r.OCSPURL, "CRLURL": r.CRLURL} {
+		if value == "" {
+			continue
+		}
+		if _, err := url.ParseRequestURI(value); err != nil {
+			return fmt.Errorf("%s must be a URL: %w", name, err)
+		}
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("Interval must be >= 0")
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("Timeout must be >= 0")
+	}
+	return nil
+}
+
+// RevocationResult is the cached outcome of a revocation check (keyed by certificate fingerprint).
+type RevocationResult struct {
+	Status           string    `json:"status"`           // see Revocation* constants
+	Method           string    `json:"method,omitempty"` // "ocsp" or "crl"
+	Source           string    `json:"source,omitempty"` // Responder or CRL URL that answered
+	CheckedAt        time.Time `json:"checked_at"`
+	RevokedAt        time.Time `json:"revoked_at,omitempty"`
+	RevocationReason int       `json:"revocation_reason,omitempty"` // RFC 5280 CRLReason
+	Error            string    `json:"error,omitempty"`
+}
+
+// cachedCRL is a downloaded and signature-checked CRL.
+type cachedCRL struct {
+	list      *x509.RevocationList
+	fetchedAt time.Time
+}
+
+// SetRevocation configures revocation checking (checks run in the watcher, never during a sync).
+func (cm *CertificateMonitor) SetRevocation(config RevocationConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.revocation = config
+	return nil
+}
+
+// revocationCheckDue reports whether a revocation check should run (caller holds cm.mu).
+func (cm *CertificateMonitor) revocationCheckDue() bool {
+	if !cm.revocation.Enabled {
+		return false
+	}
+	return time.Since(cm.lastRevocationCheck) >= cm.revocationInterval()
+}
+
+// revocationInterval returns the configured re-check interval (caller holds cm.mu).
+func (cm *CertificateMonitor) revocationInterval() time.Duration {
+	if cm.revocation.Interval > 0 {
+		return cm.revocation.Interval
+	}
+	return defaultRevocationInterval
+}
+
+// revocationTarget is a certificate (with its issuer) to check outside the lock.
+type revocationTarget struct {
+	path        string
+	fingerprint string
+	cert        *x509.Certificate
+	issuer      *x509.Certificate
+}
+
+// CheckRevocation checks all monitored certificates via OCSP (falling back to CRL).
+// Performs network requests without holding the lock. Returns true if any status changed.
+func (cm *CertificateMonitor) CheckRevocation() bool {
+	cm.mu.Lock()
+	if !cm.revocation.Enabled {
+		cm.mu.Unlock()
+		return false
+	}
+	config := cm.revocation
+	cm.lastRevocationCheck = time.Now()
+
+	var targets []revocationTarget
+	current := make(map[string]bool)
+	for _, info := range cm.certs {
+		if current[info.FingerprintSHA256] {
+			continue // Same certificate in several files
+		}
+		current[info.FingerprintSHA256] = true
+		targets = append(targets, revocationTarget{
+			path:        info.Path,
+			fingerprint: info.FingerprintSHA256,
+			cert:        info.certs[0],
+			issuer:      findIssuer(info.certs, cm.caCerts),
+		})
+	}
+	cm.mu.Unlock()
+
+	client := &http.Client{Timeout: config.Timeout}
+	if client.Timeout == 0 {
+		client.Timeout = defaultRevocationTimeout
+	}
+
+	results := make(map[string]*RevocationResult, len(targets))
+	for _, target := range targets {
+		results[target.fingerprint] = cm.checkCertificate(client, config, target)
+	}
+
+	cm.mu.Lock()
+	changed := false
+	var revoked []revocationTarget
+	for _, target := range targets {
+		result := results[target.fingerprint]
+		previous := cm.revocationResults[target.fingerprint]
+		if previous == nil || previous.Status != result.Status {
+			changed = true
+			if result.Status == RevocationRevoked {
+				revoked = append(revoked, target)
+			}
+		}
+		cm.revocationResults[target.fingerprint] = result
+	}
+	// Forget certificates that are no longer monitored
+	for fingerprint := range cm.revocationResults {
+		if !current[fingerprint] {
+			delete(cm.revocationResults, fingerprint)
+		}
+	}
+	logs := cm.logs
+	cm.mu.Unlock()
+
+	if logs != nil {
+		for _, target := range revoked {
+			logs.Warn("Certificate revoked", map[string]interface{}{
+				"path":    target.path,
+				"subject": target.cert.Subject.String(),
+				"serial":  formatHexColon(target.cert.SerialNumber.Bytes()),
+			})
+		}
+	}
+	return changed
+}
+
+// checkCertificate asks the OCSP responder first and falls back to the CRL.
+func (cm *CertificateMonitor) checkCertificate(client *http.Client, config RevocationConfig, target revocationTarget) *RevocationResult {
+	now := time.Now().UTC()
+	if isSelfSigned(target.cert) {
+		return &RevocationResult{Status: RevocationNotChecked, CheckedAt: now, Error: "self-signed certificate (trust anchor)"}
+	}
+	if target.issuer == nil {
+		return &RevocationResult{Status: RevocationError, CheckedAt: now, Error: "issuer certificate not found in file or CA"}
+	}
+
+	ocspURLs := target.cert.OCSPServer
+	if config.OCSPURL != "" {
+		ocspURLs = []string{config.OCSPURL}
+	}
+	crlURLs := target.cert.CRLDistributionPoints
+	if config.CRLURL != "" {
+		crlURLs = []string{config.CRLURL}
+	}
+	if len(ocspURLs) == 0 && len(crlURLs) == 0 {
+		return &RevocationResult{Status: RevocationNotChecked, CheckedAt: now, Error: "no OCSP responder or CRL distribution point"}
+	}
+
+	var errs []string
+	for _, responder := range ocspURLs {
+		result, err := checkOCSP(client, responder, target.cert, target.issuer)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ocsp %s: %v", responder, err))
+			continue
+		}
+		result.CheckedAt = now
+		return result
+	}
+	for _, crlURL := range crlURLs {
+		result, err := cm.checkCRL(client, crlURL, target.cert, target.issuer)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("crl %s: %v", crlURL, err))
+			continue
+		}
+		result.CheckedAt = now
+		return result
+	}
+
+	return &RevocationResult{Status: RevocationError, CheckedAt: now, Error: strings.Join(errs, "; ")}
+}
+
+// checkOCSP sends an OCSP request (POST, RFC 6960 appendix A) and verifies the response.
+func checkOCSP(client *http.Client, responder string, cert, issuer *x509.Certificate) (*RevocationResult, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Post(responder, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRevocationResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// Verifies the responder signature (issuer or delegated responder)
+	response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	result := &RevocationResult{Method: "ocsp", Source: responder}
+	switch response.Status {
+	case ocsp.Good:
+		result.Status = RevocationGood
+	case ocsp.Revoked:
+		result.Status = RevocationRevoked
+		result.RevokedAt = response.RevokedAt.UTC()
+		result.RevocationReason = response.RevocationReason
+	default:
+		result.Status = RevocationUnknown
+	}
+	return result, nil
+}
+
+// checkCRL looks up the certificate serial in a (cached) CRL signed by the issuer.
+func (cm *CertificateMonitor) checkCRL(client *http.Client, crlURL string, cert, issuer *x509.Certificate) (*RevocationResult, error) {
+	list, err := cm.fetchCRL(client, crlURL, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RevocationResult{Status: RevocationGood, Method: "crl", Source: crlURL}
+	for _, entry := range list.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			result.Status = RevocationRevoked
+			result.RevokedAt = entry.RevocationTime.UTC()
+			result.RevocationReason = entry.ReasonCode
+			break
+		}
+	}
+	return result, nil
+}
+
+// fetchCRL returns a cached CRL until NextUpdate or the check interval passes, downloading it otherwise.
+func (cm *CertificateMonitor) fetchCRL(client *http.Client, crlURL string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	cm.mu.RLock()
+	cached := cm.crlCache[crlURL]
+	interval := cm.revocationInterval()
+	cm.mu.RUnlock()
+
+	now := time.Now()
+	if cached != nil && now.Sub(cached.fetchedAt) < interval &&
+		(cached.list.NextUpdate.IsZero() || now.Before(cached.list.NextUpdate)) &&
+		cached.list.CheckSignatureFrom(issuer) == nil {
+		return cached.list, nil
+	}
+
+	resp, err := client.Get(crlURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRevocationResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// DER is standard (RFC 5280), PEM is common for local test CAs
+	if block, _ := pem.Decode(body); block != nil && block.Type == "X509 CRL" {
+		body = block.Bytes
+	}
+	list, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRL: %w", err)
+	}
+	if err := list.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("CRL not signed by issuer: %w", err)
+	}
+	if !list.NextUpdate.IsZero() && now.After(list.NextUpdate) {
+		return nil, fmt.Errorf("CRL is stale (next update was %s)", list.NextUpdate.UTC().Format(time.RFC3339))
+	}
+
+	cm.mu.Lock()
+	cm.crlCache[crlURL] = &cachedCRL{list: list, fetchedAt: now}
+	cm.mu.Unlock()
+	return list, nil
+}
+
+// findIssuer returns the issuer of certs[0]: from the same file first, then from the CA.
+func findIssuer(certs, caCerts []*x509.Certificate) *x509.Certificate {
+	for _, candidate := range certs[1:] {
+		if issuedBy(certs[0], candidate) {
+			return candidate
+		}
+	}
+	for _, candidate := range caCerts {
+		if issuedBy(certs[0], candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// revocationData converts the cached result of a certificate to component data (caller holds cm.mu).
+func (cm *CertificateMonitor) revocationData(info *CertificateInfo) (string, string, interface{}) {
+	result := cm.revocationResults[info.FingerprintSHA256]
+	if result == nil {
+		return RevocationNotChecked, "", nil
+	}
+
+	details := map[string]interface{}{
+		"method": result.Method,
+		"source": result.Source,
+		"error":  result.Error,
+	}
+	if result.Status == RevocationRevoked {
+		details["revoked_at"] = result.RevokedAt.Format(time.RFC3339)
+		details["revocation_reason"] = result.RevocationReason
+	}
+	return result.Status, result.CheckedAt.Format(time.RFC3339), details
+}
@@ -0,0 +1,234 @@
+package standard
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/update"
+)
+
+// DefaultExpiryThresholds are the days-before-expiry at which a certificate update is triggered.
+var DefaultExpiryThresholds = []int{30, 14, 7, 1}
+
+// certWatchInterval is the polling interval for CertDir changes.
+const certWatchInterval = update.Fast
+
+// certRefreshInterval bounds staleness of days_until_expiry between events (rescan without trigger).
+const certRefreshInterval = time.Hour
+
+// SetTriggerFunc sets the function to call on certificate file changes and threshold crossings.
+func (cm *CertificateMonitor) SetTriggerFunc(fn func()) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.triggerFunc = fn
+}
+
+// SetExpiryThresholds sets the days-before-expiry that trigger an update (expiry itself always triggers).
+func (cm *CertificateMonitor) SetExpiryThresholds(days []int) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.thresholds = append([]int(nil), days...)
+	cm.scheduleThresholdTimer()
+}
+
+// warningDays returns the largest expiry threshold: expiry_warning starts with the first triggered
+// update (caller holds cm.mu).
+func (cm *CertificateMonitor) warningDays() int {
+	thresholds := cm.thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultExpiryThresholds
+	}
+	return slices.Max(thresholds)
+}
+
+// Start starts watching CertDir for changes and scheduling expiry-threshold triggers.
+// While running, the monitor rescans by itself: on file changes, threshold crossings and hourly.
+func (cm *CertificateMonitor) Start() {
+	cm.mu.Lock()
+	if cm.running {
+		cm.mu.Unlock()
+		return
+	}
+	cm.running = true
+	cm.stopChan = make(chan struct{})
+	stopChan := cm.stopChan
+	cm.watchers.Add(1)
+	cm.mu.Unlock()
+
+	// Initial scan also schedules the first threshold timer
+	current := cm.fingerprint()
+	cm.mu.Lock()
+	cm.lastFingerprint = current
+	cm.mu.Unlock()
+	_ = cm.Scan()
+
+	go func() {
+		defer cm.watchers.Done()
+		cm.watch(stopChan)
+	}()
+}
+
+// Stop stops the watcher and the threshold timer. Waits for an in-flight rescan or revocation
+// check, so no scan or trigger runs after Stop returns.
+func (cm *CertificateMonitor) Stop() {
+	cm.mu.Lock()
+	if !cm.running {
+		cm.mu.Unlock()
+		return
+	}
+	cm.running = false
+	close(cm.stopChan)
+
+	if cm.thresholdTimer != nil {
+		cm.thresholdTimer.Stop()
+	}
+	cm.mu.Unlock()
+
+	cm.watchers.Wait()
+}
+
+// watch polls CertDir and triggers on create/modify/delete of certificate or key files
+// and on revocation status changes.
+func (cm *CertificateMonitor) watch(stopChan chan struct{}) {
+	ticker := time.NewTicker(time.Duration(certWatchInterval.Seconds()) * time.Second)
+	defer ticker.Stop()
+
+	// Initial revocation check (network I/O - kept off Start)
+	if cm.CheckRevocation() {
+		cm.trigger()
+	}
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		// Discovery and stat calls run outside the lock (GetData must not wait for the filesystem)
+		current := cm.fingerprint()
+		cm.mu.Lock()
+		changed := current != cm.lastFingerprint
+		cm.lastFingerprint = current
+		stale := time.Since(cm.lastScan) >= certRefreshInterval
+		cm.mu.Unlock()
+
+		if changed || stale {
+			_ = cm.Scan() // Failures are part of the data
+		}
+
+		// New certificates are checked right away, the others once per revocation interval
+		cm.mu.RLock()
+		revocationDue := changed || cm.revocationCheckDue()
+		cm.mu.RUnlock()
+		revocationChanged := revocationDue && cm.CheckRevocation() // No-op if disabled
+
+		if changed || revocationChanged {
+			cm.trigger()
+		}
+	}
+}
+
+// fingerprint summarizes name, size, mtime and mode of all watched files (mode: key permission
+// fixes are reported without waiting for the hourly rescan).
+func (cm *CertificateMonitor) fingerprint() string {
+	cm.mu.RLock()
+	certDir, discovery, caPath := cm.certDir, cm.discovery, cm.caPath
+	cm.mu.RUnlock()
+
+	var paths []string
+	files, _ := discoverFiles(certDir, discovery)
+	for _, file := range files {
+		paths = append(paths, file.path)
+		paths = append(paths, keyPathCandidates(file.path)...)
+	}
+	if caPath != "" {
+		paths = append(paths, caPath)
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s|%d|%d|%o\n", path, stat.Size(), stat.ModTime().UnixNano(), uint32(stat.Mode()))
+	}
+	return sb.String()
+}
+
+// scheduleThresholdTimer arms a timer for the next threshold crossing of any certificate (caller holds cm.mu).
+func (cm *CertificateMonitor) scheduleThresholdTimer() {
+	if !cm.running {
+		return
+	}
+	if cm.thresholdTimer != nil {
+		cm.thresholdTimer.Stop()
+		cm.thresholdTimer = nil
+	}
+
+	next := cm.nextThresholdCrossing(time.Now())
+	if next.IsZero() {
+		return
+	}
+
+	// +1s: fire just AFTER the crossing so the rescan sees the new state
+	cm.thresholdTimer = time.AfterFunc(time.Until(next)+time.Second, cm.onThresholdCrossed)
+}
+
+// nextThresholdCrossing returns the earliest future moment a chain certificate crosses a threshold or expires.
+func (cm *CertificateMonitor) nextThresholdCrossing(now time.Time) time.Time {
+	thresholds := cm.thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultExpiryThresholds
+	}
+
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	for _, info := range cm.certs {
+		for _, entry := range info.Chain {
+			consider(entry.ValidUntil) // Expiry itself
+			for _, days := range thresholds {
+				consider(entry.ValidUntil.Add(-time.Duration(days) * 24 * time.Hour))
+			}
+		}
+	}
+	return next
+}
+
+// onThresholdCrossed rescans and fires the trigger when a certificate crossed an expiry threshold.
+func (cm *CertificateMonitor) onThresholdCrossed() {
+	// Registered under the lock while running: Stop waits for it
+	cm.mu.Lock()
+	if !cm.running {
+		cm.mu.Unlock()
+		return
+	}
+	cm.watchers.Add(1)
+	cm.mu.Unlock()
+	defer cm.watchers.Done()
+
+	_ = cm.Scan() // Also re-arms the timer for the next crossing
+
+	cm.trigger()
+}
+
+// trigger calls the trigger function (if set) outside the lock.
+func (cm *CertificateMonitor) trigger() {
+	cm.mu.RLock()
+	triggerFunc := cm.triggerFunc
+	cm.mu.RUnlock()
+	if triggerFunc != nil {
+		triggerFunc()
+	}
+}
@@ -8,77 +8,188 @@ import (
 
 )
 
+// CallType distinguishes real service calls from active probes.
+type CallType string
+
+const (
+	CallTypeRequest CallType = "request" // Real call made by the service (TrackSuccess/TrackFailure)
+	CallTypeProbe   CallType = "probe"   // Synthetic check made by ConnectivityProber
+)
+
 // ConnectionCall represents a single call to a remote service.
 type ConnectionCall struct {
 	Timestamp time.Time
+	Type      CallType
 	Success   bool
 	Latency   time.Duration
 	Error     string
+	Class     ErrorClass // Set for failed calls (see ClassifyError)
 }
 
+// Connection status values (reported as "status" per outbound connection).
+const (
+	StatusUnknown   = "unknown" // No calls recorded yet
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
 // Connection tracks connectivity to a single remote service.
 type Connection struct {
 	Service string
 	URL     string
 	calls   []ConnectionCall
+	status  string // Status after the last tracked call (for transition detection)
 	mu      sync.Mutex
 }
 
+// ConnectivityStateChange describes a status transition of an outbound connection.
+type ConnectivityStateChange struct {
+	Service   string
+	URL       string
+	OldStatus string
+	NewStatus string
+	Timestamp time.Time
+	LastError string // Error of the call that caused the transition (empty on success)
+}
+
 // ConnectivityTracker tracks connectivity to multiple services.
 type ConnectivityTracker struct {
 	mu          sync.Mutex
 	connections map[string]*Connection
+
+	subMu       sync.Mutex
+	subscribers map[int]func(ConnectivityStateChange)
+	nextSubID   int
 }
 
 // NewConnectivityTracker creates a new connectivity tracker.
 func NewConnectivityTracker() *ConnectivityTracker {
 	return &ConnectivityTracker{
 		connections: make(map[string]*Connection),
+		subscribers: make(map[int]func(ConnectivityStateChange)),
+	}
+}
+
+// Subscribe registers a callback for connection status transitions.
+// Callbacks run synchronously on the tracking goroutine - keep them fast (or spawn a goroutine).
+// Returns a function that removes the subscription.
+func (t *ConnectivityTracker) Subscribe(fn func(ConnectivityStateChange)) (unsubscribe func()) {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+
+	id := t.nextSubID
+	t.nextSubID++
+	t.subscribers[id] = fn
+
+	return func() {
+		t.subMu.Lock()
+		defer t.subMu.Unlock()
+		delete(t.subscribers, id)
+	}
+}
+
+// notify delivers a state change to all subscribers (called without tracker locks held).
+func (t *ConnectivityTracker) notify(change ConnectivityStateChange) {
+	t.subMu.Lock()
+	subscribers := make([]func(ConnectivityStateChange), 0, len(t.subscribers))
+	for _, fn := range t.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	t.subMu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(change)
 	}
 }
 
 // TrackSuccess records a successful call (data-driven: just pass service, URL, latency!).
 func (t *ConnectivityTracker) TrackSuccess(service, url string, latency time.Duration) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+	t.track(service, url, ConnectionCall{
+		Type:    CallTypeRequest,
+		Success: true,
+		Latency: latency,
+	})
+}
 
-	conn := t.getOrCreateConnection(service, url)
-	conn.mu.Lock()
-	defer conn.mu.Unlock()
+// TrackFailure records a failed call (data-driven: just pass service, URL, latency, error!).
+func (t *ConnectivityTracker) TrackFailure(service, url string, latency time.Duration, errorMsg string) {
+	t.track(service, url, ConnectionCall{
+		Type:    CallTypeRequest,
+		Success: false,
+		Latency: latency,
+		Error:   errorMsg,
+		Class:   ClassifyError(errorMsg),
+	})
+}
 
-	conn.calls = append(conn.calls, ConnectionCall{
-		Timestamp: time.Now().UTC(),
-		Success:   true,
-		Latency:   latency,
+// TrackProbeSuccess records a successful active probe (called by ConnectivityProber).
+func (t *ConnectivityTracker) TrackProbeSuccess(service, url string, latency time.Duration) {
+	t.track(service, url, ConnectionCall{
+		Type:    CallTypeProbe,
+		Success: true,
+		Latency: latency,
 	})
+}
 
-	// Keep only last hour
-	t.pruneOldCalls(conn)
+// TrackProbeFailure records a failed active probe (called by ConnectivityProber).
+func (t *ConnectivityTracker) TrackProbeFailure(service, url string, latency time.Duration, errorMsg string) {
+	t.track(service, url, ConnectionCall{
+		Type:    CallTypeProbe,
+		Success: false,
+		Latency: latency,
+		Error:   errorMsg,
+		Class:   ClassifyError(errorMsg),
+	})
 }
 
-// TrackFailure records a failed call (data-driven: just pass service, URL, latency, error!).
-func (t *ConnectivityTracker) TrackFailure(service, url string, latency time.Duration, errorMsg string) {
+// track appends a call to the connection of the given service and detects status transitions.
+func (t *ConnectivityTracker) track(service, url string, call ConnectionCall) {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	conn := t.getOrCreateConnection(service, url)
+	conn := t.getOrCreateConnection(connectionKey(service, url, call.Type), service, url)
 	conn.mu.Lock()
-	defer conn.mu.Unlock()
 
-	conn.calls = append(conn.calls, ConnectionCall{
-		Timestamp: time.Now().UTC(),
-		Success:   false,
-		Latency:   latency,
-		Error:     errorMsg,
-	})
+	call.Timestamp = time.Now().UTC()
+	conn.calls = append(conn.calls, call)
 
 	// Keep only last hour
 	t.pruneOldCalls(conn)
+
+	oldStatus := conn.status
+	newStatus := conn.currentStatus()
+	conn.status = newStatus
+	connURL := conn.URL
+
+	conn.mu.Unlock()
+	t.mu.Unlock()
+
+	// A new connection that starts out healthy is not a transition worth reporting
+	if oldStatus == newStatus || (oldStatus == StatusUnknown && newStatus == StatusHealthy) {
+		return
+	}
+
+	t.notify(ConnectivityStateChange{
+		Service:   service,
+		URL:       connURL,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Timestamp: call.Timestamp,
+		LastError: call.Error,
+	})
+}
+
+// connectionKey keys real calls by service and probes by service + URL, so a probe of one
+// endpoint never overrides the state of real calls to another endpoint of the same service.
+func connectionKey(service, url string, callType CallType) string {
+	if callType == CallTypeProbe {
+		return service + " " + string(CallTypeProbe) + " " + url
+	}
+	return service
 }
 
 // getOrCreateConnection returns existing connection or creates new one.
-func (t *ConnectivityTracker) getOrCreateConnection(service, url string) *Connection {
-	if conn, exists := t.connections[service]; exists {
+func (t *ConnectivityTracker) getOrCreateConnection(key, service, url string) *Connection {
+	if conn, exists := t.connections[key]; exists {
 		return conn
 	}
 
@@ -86,8 +197,9 @@ func (t *ConnectivityTracker) getOrCreateConnection(service, url string) *Connec
 		Service: service,
 		URL:     url,
 		calls:   make([]ConnectionCall, 0),
+		status:  StatusUnknown,
 	}
-	t.connections[service] = conn
+	t.connections[key] = conn
 	return conn
 }
 
@@ -103,6 +215,41 @@ func (t *ConnectivityTracker) pruneOldCalls(conn *Connection) {
 	conn.calls = []ConnectionCall{}
 }
 
+// currentStatus derives the status from the calls in the window (caller holds conn.mu).
+func (conn *Connection) currentStatus() string {
+	if len(conn.calls) == 0 {
+		return StatusUnknown
+	}
+
+	var successCount int
+	var lastProbe *ConnectionCall
+	for i, call := range conn.calls {
+		if call.Success {
+			successCount++
+		}
+		if call.Type == CallTypeProbe {
+			lastProbe = &conn.calls[i]
+		}
+	}
+
+	return deriveStatus(float64(successCount)/float64(len(conn.calls)), lastProbe)
+}
+
+// deriveStatus maps success rate and latest probe result to a connection status.
+func deriveStatus(successRate float64, lastProbe *ConnectionCall) string {
+	// A failing latest probe means the dependency is down NOW (regardless of older calls)
+	if lastProbe != nil && !lastProbe.Success {
+		return StatusUnhealthy
+	}
+	if successRate < 0.9 {
+		return StatusUnhealthy
+	}
+	if successRate < 0.95 {
+		return StatusDegraded
+	}
+	return StatusHealthy
+}
+
 // ToComponent converts ConnectivityTracker to a Component (data-driven!).
 func (t *ConnectivityTracker) GetData() interface{} {
 	t.mu.Lock()
@@ -120,16 +267,25 @@ func (t *ConnectivityTracker) GetData() interface{} {
 
 		// Calculate stats
 		var successCount, totalCount int
+		var requestCount, probeCount, probeFailures int
 		var lastCall time.Time
+		var lastProbe *ConnectionCall
 		latencies := make([]float64, 0)
-		recentErrors := make([]string, 0)
+		failedCalls := make([]ConnectionCall, 0)
+		errorClasses := make(map[ErrorClass]*errorClassStats)
 
-		for _, call := range conn.calls {
+		for i, call := range conn.calls {
 			totalCount++
 			if call.Success {
 				successCount++
-			} else if len(recentErrors) < 5 {
-				recentErrors = append(recentErrors, call.Error)
+			} else {
+				failedCalls = append(failedCalls, call)
+				stats := errorClasses[call.Class]
+				if stats == nil {
+					stats = &errorClassStats{}
+					errorClasses[call.Class] = stats
+				}
+				stats.add(call)
 			}
 
 			latencies = append(latencies, float64(call.Latency.Milliseconds()))
@@ -137,10 +293,31 @@ func (t *ConnectivityTracker) GetData() interface{} {
 			if call.Timestamp.After(lastCall) {
 				lastCall = call.Timestamp
 			}
+
+			if call.Type == CallTypeProbe {
+				probeCount++
+				if !call.Success {
+					probeFailures++
+				}
+				lastProbe = &conn.calls[i]
+			} else {
+				requestCount++
+			}
 		}
 
 		successRate := float64(successCount) / float64(totalCount)
 
+		// Recent errors: newest first (calls are stored oldest first)
+		recentErrors := make([]string, 0, 5)
+		for i := len(failedCalls) - 1; i >= 0 && len(recentErrors) < 5; i-- {
+			recentErrors = append(recentErrors, failedCalls[i].Error)
+		}
+
+		errorClassData := make(map[string]interface{}, len(errorClasses))
+		for class, stats := range errorClasses {
+			errorClassData[string(class)] = stats.toData()
+		}
+
 		// Calculate percentiles
 		sort.Float64s(latencies)
 		p50 := percentile(latencies, 0.50)
@@ -148,14 +325,9 @@ func (t *ConnectivityTracker) GetData() interface{} {
 		p99 := percentile(latencies, 0.99)
 
 		// Determine status
-		status := "healthy"
-		if successRate < 0.9 {
-			status = "unhealthy"
-		} else if successRate < 0.95 {
-			status = "degraded"
-		}
+		status := deriveStatus(successRate, lastProbe)
 
-		outboundConnections = append(outboundConnections, map[string]interface{}{
+		connData := map[string]interface{}{
 			"service":           conn.Service,
 			"url":               conn.URL,
 			"status":            status,
@@ -168,7 +340,27 @@ func (t *ConnectivityTracker) GetData() interface{} {
 				"p99": int(p99),
 			},
 			"recent_errors": recentErrors,
-		})
+			"error_classes": errorClassData,
+			"calls_by_type_1h": map[string]interface{}{
+				string(CallTypeRequest): requestCount,
+				string(CallTypeProbe):   probeCount,
+			},
+		}
+
+		if lastProbe != nil {
+			probeData := map[string]interface{}{
+				"last_probe":      lastProbe.Timestamp.Format(time.RFC3339),
+				"last_success":    lastProbe.Success,
+				"last_latency_ms": lastProbe.Latency.Milliseconds(),
+				"failures_1h":     probeFailures,
+			}
+			if !lastProbe.Success {
+				probeData["last_error"] = lastProbe.Error
+			}
+			connData["probe"] = probeData
+		}
+
+		outboundConnections = append(outboundConnections, connData)
 
 		conn.mu.Unlock()
 	}
@@ -0,0 +1,85 @@
+package standard
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorClass categorizes a failed call so new failure modes are not hidden by repeated ones.
+type ErrorClass string
+
+const (
+	ErrorClassDNS            ErrorClass = "dns"
+	ErrorClassConnectRefused ErrorClass = "connect_refused"
+	ErrorClassTimeout        ErrorClass = "timeout"
+	ErrorClassTLSHandshake   ErrorClass = "tls_handshake"
+	ErrorClassHTTP4xx        ErrorClass = "http_4xx"
+	ErrorClassHTTP5xx        ErrorClass = "http_5xx"
+	ErrorClassOther          ErrorClass = "other"
+)
+
+// httpStatusPattern matches a leading "HTTP 503" as produced by TrackFailure callers (see HOWTO-USE.md).
+var httpStatusPattern = regexp.MustCompile(`^HTTP (\d{3})\b`)
+
+// ClassifyError maps an error message to an ErrorClass.
+// Works on strings because TrackFailure only receives the message (data-driven!).
+func ClassifyError(errorMsg string) ErrorClass {
+	// HTTP status first: the response body after "HTTP 502: " may mention anything
+	if match := httpStatusPattern.FindStringSubmatch(errorMsg); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		switch {
+		case code >= 500:
+			return ErrorClassHTTP5xx
+		case code >= 400:
+			return ErrorClassHTTP4xx
+		}
+	}
+
+	lower := strings.ToLower(errorMsg)
+
+	// DNS first: "lookup x: i/o timeout" is a resolver problem, not a slow peer
+	if containsAny(lower, []string{"no such host", "lookup ", "server misbehaving"}) {
+		return ErrorClassDNS
+	}
+	if containsAny(lower, []string{"connection refused"}) {
+		return ErrorClassConnectRefused
+	}
+	// Timeout before TLS: "TLS handshake timeout" means the peer did not answer
+	if containsAny(lower, []string{"timeout", "deadline exceeded", "timed out"}) {
+		return ErrorClassTimeout
+	}
+	if containsAny(lower, []string{"tls:", "x509:", "handshake", "certificate"}) {
+		return ErrorClassTLSHandshake
+	}
+	return ErrorClassOther
+}
+
+// errorClassStats aggregates failures of one class within the window.
+type errorClassStats struct {
+	count     int
+	lastError string
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// add records a failed call (calls arrive oldest first).
+func (s *errorClassStats) add(call ConnectionCall) {
+	s.count++
+	if s.firstSeen.IsZero() {
+		s.firstSeen = call.Timestamp
+	}
+	s.lastSeen = call.Timestamp
+	s.lastError = call.Error
+}
+
+// toData converts the stats to component data.
+func (s *errorClassStats) toData() map[string]interface{} {
+	return map[string]interface{}{
+		"count":      s.count,
+		"last_error": s.lastError,
+		"first_seen": s.firstSeen.Format(time.RFC3339),
+		"last_seen":  s.lastSeen.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,76 @@
+package standard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		errorMsg string
+		want     ErrorClass
+	}{
+		{`dial tcp: lookup ca-manager on 10.0.0.10:53: no such host`, ErrorClassDNS},
+		{`dial tcp: lookup ca-manager: i/o timeout`, ErrorClassDNS},
+		{`dial tcp: lookup ca-manager on 10.0.0.10:53: server misbehaving`, ErrorClassDNS},
+		{`dial tcp 10.0.0.5:8443: connect: connection refused`, ErrorClassConnectRefused},
+		{`dial tcp 10.0.0.5:8443: i/o timeout`, ErrorClassTimeout},
+		{`net/http: TLS handshake timeout`, ErrorClassTimeout},
+		{`context deadline exceeded (Client.Timeout exceeded while awaiting headers)`, ErrorClassTimeout},
+		{`read tcp 10.0.0.1:4711->10.0.0.5:8443: operation timed out`, ErrorClassTimeout},
+		{`tls: failed to verify certificate: x509: certificate signed by unknown authority`, ErrorClassTLSHandshake},
+		{`remote error: tls: bad certificate`, ErrorClassTLSHandshake},
+		{`HTTP 404: not found`, ErrorClassHTTP4xx},
+		{`HTTP 429: too many requests`, ErrorClassHTTP4xx},
+		{`HTTP 502: upstream connection refused`, ErrorClassHTTP5xx},
+		{`HTTP 503: timeout talking to database`, ErrorClassHTTP5xx},
+		{`HTTP 302: redirect`, ErrorClassOther},
+		{`upstream said HTTP 500`, ErrorClassOther},
+		{`EOF`, ErrorClassOther},
+		{``, ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errorMsg, func(t *testing.T) {
+			if got := ClassifyError(tt.errorMsg); got != tt.want {
+				t.Errorf("ClassifyError(%q) = %s, want %s", tt.errorMsg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectivityErrorClassesAndRecentErrors(t *testing.T) {
+	tracker := NewConnectivityTracker()
+	for _, errorMsg := range []string{"HTTP 503: a", "connection refused", "HTTP 503: b", "HTTP 500: c", "x509: expired", "HTTP 502: d"} {
+		tracker.TrackFailure("ca", "https://ca:8443", time.Millisecond, errorMsg)
+	}
+
+	data := tracker.GetData().(map[string]interface{})
+	conn := data["outbound_connections"].([]map[string]interface{})[0]
+
+	// Newest first, at most 5
+	wantRecent := []string{"HTTP 502: d", "x509: expired", "HTTP 500: c", "HTTP 503: b", "connection refused"}
+	if recent := conn["recent_errors"].([]string); !reflect.DeepEqual(recent, wantRecent) {
+		t.Errorf("recent_errors = %q, want %q", recent, wantRecent)
+	}
+
+	classes := conn["error_classes"].(map[string]interface{})
+	wantCounts := map[ErrorClass]int{ErrorClassHTTP5xx: 4, ErrorClassConnectRefused: 1, ErrorClassTLSHandshake: 1}
+	if len(classes) != len(wantCounts) {
+		t.Errorf("got %d error classes, want %d", len(classes), len(wantCounts))
+	}
+	for class, want := range wantCounts {
+		stats, ok := classes[string(class)].(map[string]interface{})
+		if !ok {
+			t.Errorf("error class %s missing", class)
+			continue
+		}
+		if stats["count"] != want {
+			t.Errorf("%s count = %v, want %d", class, stats["count"], want)
+		}
+	}
+	if last := classes[string(ErrorClassHTTP5xx)].(map[string]interface{})["last_error"]; last != "HTTP 502: d" {
+		t.Errorf("http_5xx last_error = %v, want newest", last)
+	}
+}
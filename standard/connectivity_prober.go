@@ -0,0 +1,239 @@
+package standard
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/update"
+)
+
+// ProbeType defines how a dependency is actively checked.
+type ProbeType string
+
+const (
+	ProbeHTTP ProbeType = "http" // HTTP(S) GET, success = expected status
+	ProbeTCP  ProbeType = "tcp"  // TCP connect only
+	ProbeTLS  ProbeType = "tls"  // TCP connect + full TLS handshake
+)
+
+// ProbeTarget describes a dependency that is checked periodically by ConnectivityProber.
+type ProbeTarget struct {
+	Service        string          // Dependency name as used with TrackSuccess/TrackFailure (e.g., "ca-manager")
+	Type           ProbeType       // ProbeHTTP, ProbeTCP or ProbeTLS
+	Address        string          // URL for ProbeHTTP, host:port for ProbeTCP/ProbeTLS
+	Interval       update.Interval // Probe interval (update.Fast/Medium/Slow)
+	Timeout        time.Duration   // Timeout for a single probe (must be > 0)
+	ExpectedStatus int             // Optional (ProbeHTTP): expected status code, 0 = any 2xx/3xx
+	TLSConfig      *tls.Config     // Optional (ProbeHTTP/ProbeTLS): e.g., mTLS client certificates
+}
+
+// Validate checks if all required probe fields are present.
+func (p ProbeTarget) Validate() error {
+	if p.Service == "" {
+		return fmt.Errorf("Service required")
+	}
+	if p.Address == "" {
+		return fmt.Errorf("Address required")
+	}
+	switch p.Type {
+	case ProbeHTTP:
+		if _, err := url.ParseRequestURI(p.Address); err != nil {
+			return fmt.Errorf("Address must be a URL for http probes: %w", err)
+		}
+	case ProbeTCP, ProbeTLS:
+		if _, _, err := net.SplitHostPort(p.Address); err != nil {
+			return fmt.Errorf("Address must be host:port for %s probes: %w", p.Type, err)
+		}
+	default:
+		return fmt.Errorf("invalid Type %q (must be http, tcp or tls)", p.Type)
+	}
+	switch p.Interval {
+	case update.Fast, update.Medium, update.Slow:
+	default:
+		return fmt.Errorf("Interval required (update.Fast/Medium/Slow)")
+	}
+	if p.Timeout <= 0 {
+		return fmt.Errorf("Timeout required (must be > 0)")
+	}
+	return nil
+}
+
+// trackedURL returns the URL recorded in the ConnectivityTracker for this target.
+func (p ProbeTarget) trackedURL() string {
+	if p.Type == ProbeHTTP {
+		return p.Address
+	}
+	return string(p.Type) + "://" + p.Address
+}
+
+// ConnectivityProber actively checks dependencies and records results in a ConnectivityTracker.
+// Without probing, an idle dependency that is down looks "healthy" from stale data.
+type ConnectivityProber struct {
+	tracker *ConnectivityTracker
+
+	mu       sync.Mutex
+	targets  []ProbeTarget
+	running  bool
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewConnectivityProber creates a prober that records into the given tracker.
+// Status transitions caused by probe results are reported by the tracker (see Subscribe).
+func NewConnectivityProber(tracker *ConnectivityTracker) *ConnectivityProber {
+	return &ConnectivityProber{
+		tracker: tracker,
+	}
+}
+
+// AddTarget registers a dependency for probing. Starts probing immediately if the prober is running.
+func (p *ConnectivityProber) AddTarget(target ProbeTarget) error {
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("invalid probe target %q: %w", target.Service, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, existing := range p.targets {
+		if existing.Service == target.Service {
+			return fmt.Errorf("probe target %s already registered", target.Service)
+		}
+	}
+	p.targets = append(p.targets, target)
+
+	if p.running {
+		p.startTarget(target)
+	}
+	return nil
+}
+
+// Start starts one probe loop per registered target.
+func (p *ConnectivityProber) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return
+	}
+	p.running = true
+	p.stopChan = make(chan struct{})
+
+	for _, target := range p.targets {
+		p.startTarget(target)
+	}
+}
+
+// Stop stops all probe loops and waits for in-flight probes to finish.
+func (p *ConnectivityProber) Stop() {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = false
+	close(p.stopChan)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// startTarget launches the probe loop for a single target (caller holds p.mu).
+func (p *ConnectivityProber) startTarget(target ProbeTarget) {
+	stopChan := p.stopChan
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.runTarget(target, stopChan)
+	}()
+}
+
+// runTarget probes a target immediately and then every Interval until stopped.
+func (p *ConnectivityProber) runTarget(target ProbeTarget, stopChan chan struct{}) {
+	ticker := time.NewTicker(time.Duration(target.Interval.Seconds()) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		p.probeOnce(target)
+
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeOnce executes a single probe and records it (the tracker reports status transitions).
+func (p *ConnectivityProber) probeOnce(target ProbeTarget) {
+	startTime := time.Now()
+	err := executeProbe(target)
+	latency := time.Since(startTime)
+
+	if err != nil {
+		p.tracker.TrackProbeFailure(target.Service, target.trackedURL(), latency, err.Error())
+	} else {
+		p.tracker.TrackProbeSuccess(target.Service, target.trackedURL(), latency)
+	}
+}
+
+// executeProbe performs the network check for a target.
+func executeProbe(target ProbeTarget) error {
+	switch target.Type {
+	case ProbeTCP:
+		conn, err := net.DialTimeout("tcp", target.Address, target.Timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+
+	case ProbeTLS:
+		tlsConfig := &tls.Config{}
+		if target.TLSConfig != nil {
+			tlsConfig = target.TLSConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			host, _, _ := net.SplitHostPort(target.Address)
+			tlsConfig.ServerName = host
+		}
+		dialer := &net.Dialer{Timeout: target.Timeout}
+		conn, err := tls.DialWithDialer(dialer, "tcp", target.Address, tlsConfig)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+
+	case ProbeHTTP:
+		client := &http.Client{
+			Timeout: target.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig:   target.TLSConfig,
+				DisableKeepAlives: true, // Every probe must prove a fresh connection works
+			},
+		}
+		resp, err := client.Get(target.Address)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if target.ExpectedStatus != 0 {
+			if resp.StatusCode != target.ExpectedStatus {
+				return fmt.Errorf("HTTP %d (expected %d)", resp.StatusCode, target.ExpectedStatus)
+			}
+			return nil
+		}
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("invalid probe type %q", target.Type)
+	}
+}
@@ -0,0 +1,62 @@
+package standard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectivityProbeDoesNotOverrideRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		track       func(tracker *ConnectivityTracker)
+		wantEntries int
+		wantStatus  map[string]string // url -> status
+	}{
+		{
+			name: "probe success does not hide failing calls",
+			track: func(tracker *ConnectivityTracker) {
+				tracker.TrackFailure("ca", "https://ca:8443/sign", time.Millisecond, "connection refused")
+				tracker.TrackProbeSuccess("ca", "https://ca:8443/health", time.Millisecond)
+			},
+			wantEntries: 2,
+			wantStatus:  map[string]string{"https://ca:8443/sign": StatusUnhealthy, "https://ca:8443/health": StatusHealthy},
+		},
+		{
+			name: "probe failure does not mark working calls unhealthy",
+			track: func(tracker *ConnectivityTracker) {
+				tracker.TrackSuccess("ca", "https://ca:8443/sign", time.Millisecond)
+				tracker.TrackProbeFailure("ca", "tcp://ca:9000", time.Millisecond, "i/o timeout")
+			},
+			wantEntries: 2,
+			wantStatus:  map[string]string{"https://ca:8443/sign": StatusHealthy, "tcp://ca:9000": StatusUnhealthy},
+		},
+		{
+			name: "probes of one target share an entry",
+			track: func(tracker *ConnectivityTracker) {
+				tracker.TrackProbeFailure("db", "tcp://db:5432", time.Millisecond, "connection refused")
+				tracker.TrackProbeSuccess("db", "tcp://db:5432", time.Millisecond)
+			},
+			wantEntries: 1,
+			wantStatus:  map[string]string{"tcp://db:5432": StatusUnhealthy}, // 50% success rate
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewConnectivityTracker()
+			tt.track(tracker)
+
+			data := tracker.GetData().(map[string]interface{})
+			connections := data["outbound_connections"].([]map[string]interface{})
+			if len(connections) != tt.wantEntries {
+				t.Fatalf("got %d connections, want %d", len(connections), tt.wantEntries)
+			}
+			for _, conn := range connections {
+				url := conn["url"].(string)
+				if conn["status"] != tt.wantStatus[url] {
+					t.Errorf("%s: status = %v, want %s", url, conn["status"], tt.wantStatus[url])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package standard
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Paths and patterns used during container detection (variables for non-standard mounts).
+var (
+	cgroupRoot            = "/sys/fs/cgroup"
+	serviceAccountDir     = "/var/run/secrets/kubernetes.io/serviceaccount"
+	containerEnvFile      = "/run/.containerenv" // Podman and CRI-O
+	dockerEnvFile         = "/.dockerenv"
+	containerIDPattern    = regexp.MustCompile(`[0-9a-f]{64}`)
+	mountinfoContainerIDs = regexp.MustCompile(`/containers/([0-9a-f]{64})/`)
+)
+
+// ContainerInfo describes the container, orchestrator and cgroup the service runs in.
+type ContainerInfo struct {
+	Runtime        string // "docker", "podman", "containerd", "cri-o", "cri" or "" (not containerized)
+	Orchestrator   string // "kubernetes" or ""
+	ContainerID    string
+	Pod            string // Downward API POD_NAME (fallback: HOSTNAME)
+	Namespace      string // Downward API POD_NAMESPACE (fallback: service account namespace)
+	Node           string // Downward API NODE_NAME
+	PodIP          string // Downward API POD_IP
+	ServiceAccount bool   // Service account token mounted
+	SystemdUnit    string // e.g., "ca-manager.service"
+	CgroupVersion  int    // 1 or 2 (0 = unknown)
+	CgroupPath     string
+	MemoryLimit    int64   // Bytes (0 = unlimited or unknown)
+	CPULimit       float64 // Cores from CFS quota/period (0 = unlimited or unknown)
+}
+
+// DetectContainer inspects environment, /proc/self and the cgroup filesystem.
+func DetectContainer() *ContainerInfo {
+	info := &ContainerInfo{}
+
+	cgroupData, _ := os.ReadFile("/proc/self/cgroup")
+	cgroup := string(cgroupData)
+	info.CgroupVersion, info.CgroupPath = parseCgroup(cgroup)
+
+	// Kubernetes: env injected into every pod, or the service account mount
+	if _, err := os.Stat(serviceAccountDir); err == nil {
+		info.ServiceAccount = true
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" || info.ServiceAccount {
+		info.Orchestrator = "kubernetes"
+		info.Pod = firstNonEmpty(os.Getenv("POD_NAME"), os.Getenv("HOSTNAME"))
+		info.Namespace = os.Getenv("POD_NAMESPACE")
+		if info.Namespace == "" {
+			if namespace, err := os.ReadFile(path.Join(serviceAccountDir, "namespace")); err == nil {
+				info.Namespace = strings.TrimSpace(string(namespace))
+			}
+		}
+		info.Node = os.Getenv("NODE_NAME")
+		info.PodIP = os.Getenv("POD_IP")
+	}
+
+	// Container runtime
+	containerEnv, containerEnvErr := os.ReadFile(containerEnvFile)
+	_, dockerEnvErr := os.Stat(dockerEnvFile)
+	info.Runtime = detectRuntime(runtimeMarkers{
+		cgroup:          cgroup,
+		containerVar:    os.Getenv("container"),
+		containerEnv:    string(containerEnv),
+		hasContainerEnv: containerEnvErr == nil,
+		hasDockerEnv:    dockerEnvErr == nil,
+		orchestrator:    info.Orchestrator,
+	})
+
+	// Container ID: cgroup path (v1, or v2 on the host namespace), else mountinfo (v2 with cgroupns)
+	if info.Runtime != "" {
+		info.ContainerID = containerIDPattern.FindString(cgroup)
+		if info.ContainerID == "" {
+			if mountinfo, err := os.ReadFile("/proc/self/mountinfo"); err == nil {
+				if match := mountinfoContainerIDs.FindStringSubmatch(string(mountinfo)); match != nil {
+					info.ContainerID = match[1]
+				}
+			}
+		}
+	}
+
+	// systemd unit: last *.service segment of the cgroup path
+	if os.Getenv("INVOCATION_ID") != "" || info.Runtime == "" {
+		for _, segment := range strings.Split(info.CgroupPath, "/") {
+			if strings.HasSuffix(segment, ".service") {
+				info.SystemdUnit = segment
+			}
+		}
+	}
+
+	info.MemoryLimit, info.CPULimit = readCgroupLimits(info.CgroupVersion, info.CgroupPath)
+	return info
+}
+
+// runtimeMarkers are the traces a container runtime leaves in the process environment.
+type runtimeMarkers struct {
+	cgroup          string // /proc/self/cgroup
+	containerVar    string // $container (set by Podman)
+	containerEnv    string // Content of /run/.containerenv
+	hasContainerEnv bool   // /run/.containerenv exists
+	hasDockerEnv    bool   // /.dockerenv exists
+	orchestrator    string
+}
+
+// detectRuntime identifies the container runtime ("" = not containerized).
+// Cgroup markers come first: /run/.containerenv exists under CRI-O too.
+func detectRuntime(m runtimeMarkers) string {
+	switch {
+	case m.containerVar == "podman" || strings.Contains(m.cgroup, "libpod"):
+		return "podman"
+	case strings.Contains(m.cgroup, "crio"):
+		return "cri-o"
+	case strings.Contains(m.cgroup, "containerd"):
+		return "containerd"
+	case m.hasDockerEnv || strings.Contains(m.cgroup, "docker"):
+		return "docker"
+	case m.hasContainerEnv:
+		return containerEnvRuntime(m.containerEnv, m.orchestrator)
+	case m.orchestrator != "":
+		return "cri" // Pods always run in a container, runtime not identifiable
+	default:
+		return ""
+	}
+}
+
+// containerEnvRuntime identifies the runtime from /run/.containerenv: Podman writes
+// engine="podman-<version>", CRI-O leaves the file empty (only seen in pods).
+func containerEnvRuntime(containerEnv, orchestrator string) string {
+	for _, line := range strings.Split(containerEnv, "\n") {
+		if engine, ok := strings.CutPrefix(line, "engine="); ok {
+			if strings.Contains(engine, "cri-o") {
+				return "cri-o"
+			}
+			return "podman"
+		}
+	}
+	if orchestrator != "" {
+		return "cri-o"
+	}
+	return "podman"
+}
+
+// parseCgroup returns cgroup version and the process's cgroup path (v1: memory controller).
+func parseCgroup(cgroup string) (int, string) {
+	if _, err := os.Stat(path.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		for _, line := range strings.Split(cgroup, "\n") {
+			if strings.HasPrefix(line, "0::") {
+				return 2, strings.TrimPrefix(line, "0::")
+			}
+		}
+		return 2, ""
+	}
+
+	for _, line := range strings.Split(cgroup, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			if controller == "memory" {
+				return 1, parts[2]
+			}
+		}
+	}
+	if cgroup != "" {
+		return 1, ""
+	}
+	return 0, ""
+}
+
+// readCgroupLimits reads memory and CPU limits. The process's own cgroup is tried first
+// (host namespace), then the cgroup root (container with its own cgroup namespace).
+func readCgroupLimits(version int, cgroupPath string) (int64, float64) {
+	var memoryLimit int64
+	var cpuLimit float64
+
+	switch version {
+	case 2:
+		// memory.max: "max" or bytes; cpu.max: "max 100000" or "<quota> <period>"
+		if value, ok := readCgroupFile(cgroupPath, "memory.max"); ok && value != "max" {
+			memoryLimit, _ = strconv.ParseInt(value, 10, 64)
+		}
+		if value, ok := readCgroupFile(cgroupPath, "cpu.max"); ok {
+			fields := strings.Fields(value)
+			if len(fields) == 2 && fields[0] != "max" {
+				cpuLimit = cpuCores(fields[0], fields[1])
+			}
+		}
+
+	case 1:
+		if value, ok := readCgroupFile(path.Join("memory", cgroupPath), "memory.limit_in_bytes"); ok {
+			limit, _ := strconv.ParseInt(value, 10, 64)
+			if limit < 1<<62 { // Unlimited is reported as a page-aligned maximum int64
+				memoryLimit = limit
+			}
+		}
+		quota, quotaOK := readCgroupFile(path.Join("cpu", cgroupPath), "cpu.cfs_quota_us")
+		period, periodOK := readCgroupFile(path.Join("cpu", cgroupPath), "cpu.cfs_period_us")
+		if quotaOK && periodOK && quota != "-1" {
+			cpuLimit = cpuCores(quota, period)
+		}
+	}
+
+	return memoryLimit, cpuLimit
+}
+
+// readCgroupFile reads a cgroup control file below cgroupRoot, falling back from dir to its controller root.
+func readCgroupFile(dir, name string) (string, bool) {
+	candidates := []string{path.Join(cgroupRoot, dir, name)}
+	if controller := strings.SplitN(dir, "/", 2)[0]; controller != dir {
+		candidates = append(candidates, path.Join(cgroupRoot, controller, name)) // v1 with cgroupns
+	}
+	candidates = append(candidates, path.Join(cgroupRoot, name)) // v2 with cgroupns
+
+	for _, candidate := range candidates {
+		if data, err := os.ReadFile(candidate); err == nil {
+			return strings.TrimSpace(string(data)), true
+		}
+	}
+	return "", false
+}
+
+// cpuCores converts a CFS quota/period pair to cores.
+func cpuCores(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// data converts ContainerInfo to component data.
+func (c *ContainerInfo) data() map[string]interface{} {
+	return map[string]interface{}{
+		"runtime":            c.Runtime,
+		"orchestrator":       c.Orchestrator,
+		"container_id":       c.ContainerID,
+		"pod":                c.Pod,
+		"namespace":          c.Namespace,
+		"node":               c.Node,
+		"pod_ip":             c.PodIP,
+		"service_account":    c.ServiceAccount,
+		"systemd_unit":       c.SystemdUnit,
+		"cgroup_version":     c.CgroupVersion,
+		"cgroup_path":        c.CgroupPath,
+		"memory_limit_bytes": c.MemoryLimit,
+		"cpu_limit_cores":    c.CPULimit,
+	}
+}
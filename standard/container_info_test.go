@@ -0,0 +1,219 @@
+package standard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectRuntime(t *testing.T) {
+	id := strings.Repeat("ab12", 16)
+
+	tests := []struct {
+		name    string
+		markers runtimeMarkers
+		want    string
+	}{
+		{
+			name:    "docker cgroup v1",
+			markers: runtimeMarkers{cgroup: "12:memory:/docker/" + id + "\n11:cpu,cpuacct:/docker/" + id},
+			want:    "docker",
+		},
+		{
+			name:    "docker cgroup v2 host namespace",
+			markers: runtimeMarkers{cgroup: "0::/system.slice/docker-" + id + ".scope"},
+			want:    "docker",
+		},
+		{
+			name:    "docker with private cgroup namespace",
+			markers: runtimeMarkers{cgroup: "0::/", hasDockerEnv: true},
+			want:    "docker",
+		},
+		{
+			name:    "rootless podman",
+			markers: runtimeMarkers{cgroup: "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + id + ".scope"},
+			want:    "podman",
+		},
+		{
+			name:    "podman container variable",
+			markers: runtimeMarkers{cgroup: "0::/", containerVar: "podman", hasContainerEnv: true},
+			want:    "podman",
+		},
+		{
+			name:    "podman containerenv only",
+			markers: runtimeMarkers{cgroup: "0::/", containerEnv: "engine=\"podman-4.9.3\"\nname=\"svc\"\n", hasContainerEnv: true},
+			want:    "podman",
+		},
+		{
+			name: "cri-o pod (containerenv exists too)",
+			markers: runtimeMarkers{
+				cgroup:          "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice/crio-" + id + ".scope",
+				hasContainerEnv: true,
+				orchestrator:    "kubernetes",
+			},
+			want: "cri-o",
+		},
+		{
+			name:    "cri-o pod with private cgroup namespace",
+			markers: runtimeMarkers{cgroup: "0::/", hasContainerEnv: true, orchestrator: "kubernetes"},
+			want:    "cri-o",
+		},
+		{
+			name:    "cri-o engine in containerenv",
+			markers: runtimeMarkers{cgroup: "0::/", containerEnv: "engine=\"cri-o\"\n", hasContainerEnv: true},
+			want:    "cri-o",
+		},
+		{
+			name:    "containerd pod",
+			markers: runtimeMarkers{cgroup: "0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + id + ".scope", orchestrator: "kubernetes"},
+			want:    "containerd",
+		},
+		{
+			name:    "pod without runtime markers",
+			markers: runtimeMarkers{cgroup: "0::/", orchestrator: "kubernetes"},
+			want:    "cri",
+		},
+		{
+			name:    "systemd service on the host",
+			markers: runtimeMarkers{cgroup: "0::/system.slice/ca-manager.service"},
+			want:    "",
+		},
+		{
+			name:    "nothing known",
+			markers: runtimeMarkers{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectRuntime(tt.markers); got != tt.want {
+				t.Errorf("detectRuntime = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setCgroupRoot points cgroupRoot at a test directory for the duration of the test.
+func setCgroupRoot(t *testing.T, dir string) {
+	previous := cgroupRoot
+	cgroupRoot = dir
+	t.Cleanup(func() { cgroupRoot = previous })
+}
+
+// writeCgroupFile creates a control file below the test cgroup root.
+func writeCgroupFile(t *testing.T, rel, content string) {
+	t.Helper()
+	path := filepath.Join(cgroupRoot, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCgroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		unified     bool // cgroup.controllers exists (v2 mounted)
+		cgroup      string
+		wantVersion int
+		wantPath    string
+	}{
+		{name: "v2", unified: true, cgroup: "0::/system.slice/ca-manager.service\n", wantVersion: 2, wantPath: "/system.slice/ca-manager.service"},
+		{name: "v2 without entry", unified: true, cgroup: "", wantVersion: 2},
+		{name: "v1 memory", cgroup: "5:cpu,cpuacct:/docker/x\n4:memory:/docker/x\n1:name=systemd:/docker/x", wantVersion: 1, wantPath: "/docker/x"},
+		{name: "v1 combined controllers", cgroup: "3:cpu,memory:/svc", wantVersion: 1, wantPath: "/svc"},
+		{name: "v1 without memory", cgroup: "2:cpu:/svc", wantVersion: 1},
+		{name: "unknown", cgroup: "", wantVersion: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCgroupRoot(t, t.TempDir())
+			if tt.unified {
+				writeCgroupFile(t, "cgroup.controllers", "cpu memory")
+			}
+			version, cgroupPath := parseCgroup(tt.cgroup)
+			if version != tt.wantVersion || cgroupPath != tt.wantPath {
+				t.Errorf("parseCgroup = (%d, %q), want (%d, %q)", version, cgroupPath, tt.wantVersion, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestReadCgroupLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    int
+		cgroupPath string
+		files      map[string]string
+		wantMemory int64
+		wantCPU    float64
+	}{
+		{
+			name:       "v2 own cgroup",
+			version:    2,
+			cgroupPath: "/system.slice/svc.service",
+			files:      map[string]string{"system.slice/svc.service/memory.max": "536870912\n", "system.slice/svc.service/cpu.max": "150000 100000\n"},
+			wantMemory: 512 << 20,
+			wantCPU:    1.5,
+		},
+		{
+			name:       "v2 cgroup namespace root",
+			version:    2,
+			cgroupPath: "/",
+			files:      map[string]string{"memory.max": "1073741824", "cpu.max": "50000 100000"},
+			wantMemory: 1 << 30,
+			wantCPU:    0.5,
+		},
+		{
+			name:       "v2 unlimited",
+			version:    2,
+			cgroupPath: "/",
+			files:      map[string]string{"memory.max": "max", "cpu.max": "max 100000"},
+		},
+		{
+			name:       "v1 limits",
+			version:    1,
+			cgroupPath: "/docker/x",
+			files: map[string]string{
+				"memory/docker/x/memory.limit_in_bytes": "268435456",
+				"cpu/docker/x/cpu.cfs_quota_us":         "200000",
+				"cpu/docker/x/cpu.cfs_period_us":        "100000",
+			},
+			wantMemory: 256 << 20,
+			wantCPU:    2,
+		},
+		{
+			name:       "v1 unlimited via controller root",
+			version:    1,
+			cgroupPath: "/docker/x",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "9223372036854771712",
+				"cpu/cpu.cfs_quota_us":         "-1",
+				"cpu/cpu.cfs_period_us":        "100000",
+			},
+		},
+		{
+			name:    "unknown version",
+			version: 0,
+			files:   map[string]string{"memory.max": "1024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCgroupRoot(t, t.TempDir())
+			for rel, content := range tt.files {
+				writeCgroupFile(t, rel, content)
+			}
+			memory, cpu := readCgroupLimits(tt.version, tt.cgroupPath)
+			if memory != tt.wantMemory || cpu != tt.wantCPU {
+				t.Errorf("readCgroupLimits = (%d, %v), want (%d, %v)", memory, cpu, tt.wantMemory, tt.wantCPU)
+			}
+		})
+	}
+}
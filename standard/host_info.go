@@ -0,0 +1,239 @@
+package standard
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tcpListenState is the LISTEN state in /proc/net/tcp*.
+const tcpListenState = "0A"
+
+// HostInfo identifies the machine an instance runs on (static, detected once).
+type HostInfo struct {
+	Hostname      string
+	OSID          string // /etc/os-release ID (e.g., "debian")
+	OSVersion     string // /etc/os-release VERSION_ID (e.g., "12")
+	OSPrettyName  string // /etc/os-release PRETTY_NAME
+	KernelRelease string // uname -r
+	KernelVersion string // uname -v
+	Arch          string
+}
+
+// ListeningSocket is a TCP socket the process is listening on.
+type ListeningSocket struct {
+	Protocol string `json:"protocol"` // "tcp" or "tcp6"
+	Address  string `json:"address"`
+	Port     int    `json:"port"`
+}
+
+// DetectHost reads hostname, /etc/os-release and the kernel version.
+func DetectHost() *HostInfo {
+	hostname, _ := os.Hostname()
+	host := &HostInfo{
+		Hostname: hostname,
+		Arch:     runtime.GOARCH,
+	}
+
+	// Same values as uname -r / uname -v (procfs avoids a syscall package per OS)
+	if release, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		host.KernelRelease = strings.TrimSpace(string(release))
+	}
+	if version, err := os.ReadFile("/proc/sys/kernel/version"); err == nil {
+		host.KernelVersion = strings.TrimSpace(string(version))
+	}
+
+	// os-release: /etc first, /usr/lib as the documented fallback
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		release, err := readOSRelease(path)
+		if err != nil {
+			continue
+		}
+		host.OSID = release["ID"]
+		host.OSVersion = release["VERSION_ID"]
+		host.OSPrettyName = release["PRETTY_NAME"]
+		break
+	}
+
+	return host
+}
+
+// readOSRelease parses KEY=value lines (values optionally quoted).
+func readOSRelease(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	release := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		release[key] = value
+	}
+	return release, scanner.Err()
+}
+
+// interfaceAddresses returns non-loopback interface addresses (e.g., "eth0": ["10.0.0.5/24"]).
+func interfaceAddresses() map[string][]string {
+	addresses := make(map[string][]string)
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return addresses
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			addresses[iface.Name] = append(addresses[iface.Name], addr.String())
+		}
+	}
+	return addresses
+}
+
+// listeningSockets returns the TCP sockets this process listens on (/proc/net/tcp* filtered by
+// the socket inodes in /proc/self/fd). Returns an error if procfs is unavailable (non-Linux).
+func listeningSockets() ([]ListeningSocket, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil, err
+	}
+	inodes := make(map[string]bool)
+	for _, entry := range entries {
+		link, err := os.Readlink("/proc/self/fd/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(link, "socket:[") {
+			inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] = true
+		}
+	}
+
+	sockets := []ListeningSocket{}
+	seen := make(map[ListeningSocket]bool)
+	for _, protocol := range []string{"tcp", "tcp6"} {
+		file, err := os.Open("/proc/net/" + protocol)
+		if err != nil {
+			if protocol == "tcp6" {
+				continue // IPv6 disabled
+			}
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Scan() // Header
+		for scanner.Scan() {
+			// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 || fields[3] != tcpListenState || !inodes[fields[9]] {
+				continue
+			}
+			address, port, err := parseProcNetAddress(fields[1])
+			if err != nil {
+				continue
+			}
+			socket := ListeningSocket{Protocol: protocol, Address: address, Port: port}
+			if !seen[socket] {
+				seen[socket] = true
+				sockets = append(sockets, socket)
+			}
+		}
+		file.Close()
+	}
+
+	sort.Slice(sockets, func(i, j int) bool {
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+		return sockets[i].Protocol < sockets[j].Protocol
+	})
+	return sockets, nil
+}
+
+// parseProcNetAddress decodes "0100007F:1F90" (IPv4) or 32 hex digits + port (IPv6).
+// Addresses are stored as 32-bit words in host byte order (little-endian assumed: amd64/arm64).
+func parseProcNetAddress(value string) (string, int, error) {
+	hexAddress, hexPort, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid address %q", value)
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	raw, err := hex.DecodeString(hexAddress)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0, fmt.Errorf("invalid address %q", value)
+	}
+
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for i := 0; i < 4; i++ {
+			ip[word+i] = raw[word+3-i]
+		}
+	}
+	return ip.String(), int(port), nil
+}
+
+// networkData returns addresses, listening sockets and whether the configured port is listened on.
+func networkData(configuredPort int) map[string]interface{} {
+	data := map[string]interface{}{
+		"addresses": interfaceAddresses(),
+	}
+
+	sockets, err := listeningSockets()
+	if err != nil {
+		// Unknown (no procfs) - not flagged
+		data["listening"] = nil
+		data["port_listening"] = nil
+		return data
+	}
+	data["listening"] = sockets
+
+	listening := false
+	for _, socket := range sockets {
+		if socket.Port == configuredPort {
+			listening = true
+			break
+		}
+	}
+	data["port_listening"] = listening
+	if !listening {
+		data["port_warning"] = fmt.Sprintf("configured port %d is not being listened on by this process", configuredPort)
+	}
+	return data
+}
+
+// data converts HostInfo to component data.
+func (h *HostInfo) data() map[string]interface{} {
+	return map[string]interface{}{
+		"hostname":       h.Hostname,
+		"os_id":          h.OSID,
+		"os_version":     h.OSVersion,
+		"os_pretty_name": h.OSPrettyName,
+		"kernel_release": h.KernelRelease,
+		"kernel_version": h.KernelVersion,
+		"arch":           h.Arch,
+	}
+}
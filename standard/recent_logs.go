@@ -2,6 +2,7 @@
 package standard
 
 import (
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -17,6 +18,22 @@ const (
 	LevelDebug LogLevel = "DEBUG"
 )
 
+// levelRank orders levels by severity (higher = more severe).
+var levelRank = map[LogLevel]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
+}
+
+// Validate checks that the level is known.
+func (l LogLevel) Validate() error {
+	if _, ok := levelRank[l]; !ok {
+		return fmt.Errorf("unknown log level %q (must be DEBUG, INFO, WARN or ERROR)", l)
+	}
+	return nil
+}
+
 // LogEntry represents a single log entry.
 type LogEntry struct {
 	Timestamp time.Time              `json:"timestamp"`
@@ -30,7 +47,8 @@ type RecentLogs struct {
 	mu          sync.Mutex
 	entries     []LogEntry
 	maxEntries  int
-	triggerFunc func() // Called on Error/Warn to trigger immediate sync
+	override    LogLevel // Minimum level kept, set by introspection (empty = keep all)
+	triggerFunc func()   // Called on Error/Warn to trigger immediate sync
 }
 
 // NewRecentLogs creates a new RecentLogs tracker.
@@ -52,6 +70,27 @@ func (r *RecentLogs) SetTriggerFunc(fn func()) {
 	r.triggerFunc = fn
 }
 
+// SetMinLevelOverride sets the minimum level kept in the ringbuffer, requested by introspection
+// (empty = keep all). Entries below still go to stdout/journald.
+func (r *RecentLogs) SetMinLevelOverride(level LogLevel) error {
+	if level != "" {
+		if err := level.Validate(); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.override = level
+	return nil
+}
+
+// MinLevelOverride returns the level requested by introspection (empty = none).
+func (r *RecentLogs) MinLevelOverride() LogLevel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.override
+}
+
 // Log adds a log entry with context (data-driven: pass level + message + context!).
 // Context must be non-empty to ensure structured logging.
 // IMPORTANT: Also logs to stdout/journald for visibility!
@@ -64,6 +103,12 @@ func (r *RecentLogs) Log(level LogLevel, message string, context map[string]inte
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Below the minimum level: stdout only
+	if r.override != "" && levelRank[level] < levelRank[r.override] {
+		log.Printf("[%s] %s %v", level, message, context)
+		return
+	}
+
 	entry := LogEntry{
 		Timestamp: time.Now().UTC(),
 		Level:     level,
@@ -160,12 +205,13 @@ func (r *RecentLogs) GetData() interface{} {
 	return map[string]interface{}{
 		"entries": r.entries,
 		"stats": map[string]interface{}{
-			"total_count":    len(r.entries),
-			"errors_count":   errorCount,
-			"warnings_count": warnCount,
-			"info_count":     infoCount,
-			"debug_count":    debugCount,
-			"max_entries":    r.maxEntries,
+			"total_count":        len(r.entries),
+			"errors_count":       errorCount,
+			"warnings_count":     warnCount,
+			"info_count":         infoCount,
+			"debug_count":        debugCount,
+			"max_entries":        r.maxEntries,
+			"min_level_override": r.override,
 		},
 	}
 }
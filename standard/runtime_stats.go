@@ -0,0 +1,196 @@
+package standard
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/update"
+)
+
+// runtimeMetrics are the runtime/metrics samples read for the runtime component.
+var runtimeMetrics = []string{
+	"/sched/goroutines:goroutines",
+	"/sched/gomaxprocs:threads",
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/total:bytes",
+	"/gc/heap/goal:bytes",
+	"/gc/cycles/total:gc-cycles",
+	"/sched/pauses/total/gc:seconds",
+}
+
+// clockTicksPerSecond is USER_HZ for /proc/self/stat CPU times (100 on all common Linux platforms).
+const clockTicksPerSecond = 100
+
+// RuntimeStats reports process health: goroutines, heap, GC pauses, RSS, file descriptors and uptime.
+// A sample is kept for one interval, so syncs in between do not resend changed numbers.
+type RuntimeStats struct {
+	startTime time.Time
+	interval  update.Interval
+
+	mu        sync.Mutex
+	sample    map[string]interface{}
+	sampledAt time.Time
+}
+
+// NewRuntimeStats creates runtime stats sampled at most once per interval.
+func NewRuntimeStats(startTime time.Time, interval update.Interval) *RuntimeStats {
+	return &RuntimeStats{
+		startTime: startTime,
+		interval:  interval,
+	}
+}
+
+// GetData returns the current sample (re-sampled when older than the interval).
+func (r *RuntimeStats) GetData() interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 1s tolerance: the registry collects exactly when the interval has passed
+	maxAge := time.Duration(r.interval.Seconds())*time.Second - time.Second
+	if r.sample == nil || time.Since(r.sampledAt) >= maxAge {
+		r.sampledAt = time.Now()
+		r.sample = r.collect(r.sampledAt)
+	}
+	return r.sample
+}
+
+// collect reads runtime/metrics and /proc/self.
+func (r *RuntimeStats) collect(now time.Time) map[string]interface{} {
+	samples := make([]metrics.Sample, len(runtimeMetrics))
+	for i, name := range runtimeMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	goData := make(map[string]interface{})
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			value := sample.Value.Uint64()
+			switch sample.Name {
+			case "/sched/goroutines:goroutines":
+				goData["goroutines"] = value
+			case "/sched/gomaxprocs:threads":
+				goData["gomaxprocs"] = value
+			case "/memory/classes/heap/objects:bytes":
+				goData["heap_alloc_bytes"] = value
+			case "/memory/classes/total:bytes":
+				goData["total_memory_bytes"] = value
+			case "/gc/heap/goal:bytes":
+				goData["heap_goal_bytes"] = value
+			case "/gc/cycles/total:gc-cycles":
+				goData["gc_cycles"] = value
+			}
+		case metrics.KindFloat64Histogram:
+			// GC stop-the-world pauses since process start
+			histogram := sample.Value.Float64Histogram()
+			goData["gc_pause_p50_ms"] = histogramQuantile(histogram, 0.50) * 1000
+			goData["gc_pause_p99_ms"] = histogramQuantile(histogram, 0.99) * 1000
+			goData["gc_pause_max_ms"] = histogramQuantile(histogram, 1.0) * 1000
+		}
+		// KindBad: metric not supported by this Go version - omitted
+	}
+
+	return map[string]interface{}{
+		"uptime_seconds": int64(now.Sub(r.startTime).Seconds()),
+		"sampled_at":     now.UTC().Format(time.RFC3339),
+		"go":             goData,
+		"process":        readProcessStats(),
+	}
+}
+
+// histogramQuantile returns the upper bound of the bucket containing quantile q (0 if empty).
+func histogramQuantile(histogram *metrics.Float64Histogram, q float64) float64 {
+	var total uint64
+	for _, count := range histogram.Counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	threshold := uint64(math.Ceil(q * float64(total)))
+	var cumulative uint64
+	for i, count := range histogram.Counts {
+		cumulative += count
+		if count > 0 && cumulative >= threshold {
+			upper := histogram.Buckets[i+1]
+			if math.IsInf(upper, 1) {
+				upper = histogram.Buckets[i] // Open-ended bucket: report its lower bound
+			}
+			return upper
+		}
+	}
+	return 0
+}
+
+// readProcessStats reads RSS, threads, file descriptors and CPU time from /proc/self (Linux only).
+// Fields that cannot be read are omitted.
+func readProcessStats() map[string]interface{} {
+	data := make(map[string]interface{})
+
+	// /proc/self/status: VmRSS, VmHWM (kB), Threads
+	if file, err := os.Open("/proc/self/status"); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 {
+				continue
+			}
+			value, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch fields[0] {
+			case "VmRSS:":
+				data["rss_bytes"] = value * 1024
+			case "VmHWM:":
+				data["rss_peak_bytes"] = value * 1024
+			case "Threads:":
+				data["threads"] = value
+			}
+		}
+		file.Close()
+	}
+
+	// Open file descriptors vs. soft limit
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		data["open_fds"] = len(entries)
+	}
+	if limits, err := os.ReadFile("/proc/self/limits"); err == nil {
+		for _, line := range strings.Split(string(limits), "\n") {
+			if strings.HasPrefix(line, "Max open files") {
+				fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+				if len(fields) > 0 {
+					if value, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+						data["max_fds"] = value
+					}
+				}
+			}
+		}
+	}
+
+	// /proc/self/stat: utime (14) and stime (15) in clock ticks; comm (2) may contain spaces
+	if stat, err := os.ReadFile("/proc/self/stat"); err == nil {
+		content := string(stat)
+		if end := strings.LastIndex(content, ")"); end >= 0 {
+			fields := strings.Fields(content[end+1:]) // fields[0] = state (field 3)
+			if len(fields) > 12 {
+				if utime, err := strconv.ParseFloat(fields[11], 64); err == nil {
+					data["cpu_user_seconds"] = utime / clockTicksPerSecond
+				}
+				if stime, err := strconv.ParseFloat(fields[12], 64); err == nil {
+					data["cpu_system_seconds"] = stime / clockTicksPerSecond
+				}
+			}
+		}
+	}
+
+	return data
+}
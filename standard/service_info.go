@@ -16,6 +16,8 @@ type ServiceType string
 const (
 	ServiceTypeSystemd    ServiceType = "systemd"
 	ServiceTypeDocker     ServiceType = "docker"
+	ServiceTypePodman     ServiceType = "podman"
+	ServiceTypeKubernetes ServiceType = "kubernetes"
 	ServiceTypeStandalone ServiceType = "standalone"
 )
 
@@ -43,15 +45,29 @@ type ServiceInfo struct {
 	// Read from SERVICE_TYPE environment variable
 	// Used by service-authz for policy evaluation (same for all instances of a service)
 	PlatformServiceType      string
+	// Build and VCS metadata of the binary (nil if built without module support)
+	Build                    *BuildInfo
+	// Container runtime, Kubernetes pod/namespace/node, systemd unit, cgroup limits
+	Container                *ContainerInfo
+	// Hostname, OS release, kernel (network addresses and listening ports are read per collection)
+	Host                     *HostInfo
 }
 
 // AutoDetect creates ServiceInfo with auto-detected runtime information.
+// An empty version falls back to the build metadata (see BuildVersion).
 func AutoDetect(serviceName, version string, port int) *ServiceInfo {
 	// Capture actual UTC timestamp at creation (STATIC!)
 	startTime := time.Now().UTC()
 
-	// Detect service type
-	serviceType := detectServiceType()
+	// Build metadata (Go version, VCS, dependencies)
+	build := ReadBuildInfo()
+	if version == "" {
+		version = BuildVersion()
+	}
+
+	// Detect container/orchestrator and service type
+	container := DetectContainer()
+	serviceType := detectServiceType(container)
 
 	// Get binary path (current executable)
 	binaryPath, _ := os.Executable()
@@ -98,6 +114,9 @@ func AutoDetect(serviceName, version string, port int) *ServiceInfo {
 		GID:                    gid,
 		InstanceID:             instanceID,
 		PlatformServiceType:    platformServiceType,
+		Build:                  build,
+		Container:              container,
+		Host:                   DetectHost(),
 	}
 }
 
@@ -124,29 +143,42 @@ func (s *ServiceInfo) GetData() interface{} {
 		"service_type":            s.PlatformServiceType,  // ADR-036: Service type for policy lookup
 	}
 
+	if s.Build != nil {
+		data["build"] = s.Build.data()
+	}
+	if s.Container != nil {
+		data["container"] = s.Container.data()
+	}
+	if s.Host != nil {
+		data["host"] = s.Host.data()
+	}
+
+	// Dynamic: the service may start (or stop) listening after the client was created
+	data["network"] = networkData(s.Port)
+
 	return data
 }
 
 // detectServiceType determines how the service is running.
-func detectServiceType() ServiceType {
+func detectServiceType(container *ContainerInfo) ServiceType {
+	// Orchestrator first: pods run in docker/containerd/cri-o containers
+	if container.Orchestrator == "kubernetes" {
+		return ServiceTypeKubernetes
+	}
+
 	// Check for systemd (INVOCATION_ID environment variable)
 	if os.Getenv("INVOCATION_ID") != "" {
 		return ServiceTypeSystemd
 	}
 
-	// Check for Docker (/.dockerenv file or cgroup)
-	if _, err := os.Stat("/.dockerenv"); err == nil {
+	// Containers (/.dockerenv, /run/.containerenv, container env, cgroup paths)
+	switch container.Runtime {
+	case "podman":
+		return ServiceTypePodman
+	case "docker", "containerd", "cri-o":
 		return ServiceTypeDocker
 	}
 
-	// Check cgroup for docker/containerd
-	if data, err := os.ReadFile("/proc/self/cgroup"); err == nil {
-		cgroup := string(data)
-		if containsAny(cgroup, []string{"docker", "containerd"}) {
-			return ServiceTypeDocker
-		}
-	}
-
 	// Check if PID 1 is systemd
 	if data, err := os.ReadFile("/proc/1/comm"); err == nil {
 		if string(data) == "systemd\n" {
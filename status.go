@@ -0,0 +1,234 @@
+package introspection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/st-keller/introspection-client/v2/update"
+)
+
+// statusInterval is the update interval of the introspection-client component.
+const statusInterval = update.Slow
+
+// Status is a snapshot of the sync system (see Client.Status).
+type Status struct {
+	Running             bool
+	LastAttempt         time.Time    // Start of the last sync attempt (zero = none yet)
+	LastSuccess         time.Time    // End of the last successful sync (zero = none yet)
+	LastError           string       // Error of the last failed sync (empty after a success)
+	LastFailureClass    FailureClass // Class of LastError (empty after a success)
+	ConsecutiveFailures int
+	CurrentBackoff      time.Duration         // Remaining pause before the next sync (backoff, Retry-After, permanent failure)
+	Syncs               int64                 // Successful syncs
+	Failures            int64                 // Failed syncs
+	BytesSent           int64                 // Request bytes on the wire (after compression), all phases
+	Phases              map[string]PhaseStats // "roots", "checksums", "components"
+	LastDuration        time.Duration         // Duration of the last successful sync
+	AverageDuration     time.Duration         // Average duration of successful syncs
+	MaxDuration         time.Duration         // Longest successful sync
+}
+
+// PhaseStats counts the requests of one sync phase.
+type PhaseStats struct {
+	Requests  int64 // Requests sent (including failed ones)
+	Bytes     int64 // Request bytes on the wire
+	Items     int64 // Entries sent: entity roots, checksums or components
+	LastItems int   // Entries of the last request
+}
+
+// syncStats accumulates the counters behind Status.
+type syncStats struct {
+	mu                  sync.Mutex
+	lastAttempt         time.Time
+	lastSuccess         time.Time
+	lastError           string
+	lastFailureClass    FailureClass
+	consecutiveFailures int
+	syncs               int64
+	failures            int64
+	bytesSent           int64
+	phases              map[string]PhaseStats
+	lastDuration        time.Duration
+	totalDuration       time.Duration
+	maxDuration         time.Duration
+
+	// introspection-client component: snapshot kept for one interval (or until health changes)
+	snapshot        map[string]interface{}
+	snapshotAt      time.Time
+	snapshotHealthy bool
+}
+
+// recordAttempt marks the start of a sync attempt.
+func (s *syncStats) recordAttempt(start time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAttempt = start
+}
+
+// recordSuccess records a successful sync that started at start.
+func (s *syncStats) recordSuccess(start time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	duration := now.Sub(start)
+	s.lastSuccess = now
+	s.lastError = ""
+	s.lastFailureClass = ""
+	s.consecutiveFailures = 0
+	s.syncs++
+	s.lastDuration = duration
+	s.totalDuration += duration
+	s.maxDuration = max(s.maxDuration, duration)
+}
+
+// recordFailure records a failed sync.
+func (s *syncStats) recordFailure(err error, class FailureClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err.Error()
+	s.lastFailureClass = class
+	s.consecutiveFailures++
+	s.failures++
+}
+
+// recordRequest counts a request of a phase (wireBytes after compression).
+func (s *syncStats) recordRequest(phase string, wireBytes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.phases == nil {
+		s.phases = make(map[string]PhaseStats)
+	}
+	stats := s.phases[phase]
+	stats.Requests++
+	stats.Bytes += int64(wireBytes)
+	s.phases[phase] = stats
+	s.bytesSent += int64(wireBytes)
+}
+
+// recordItems counts the entries sent in a phase.
+func (s *syncStats) recordItems(phase string, items int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.phases == nil {
+		s.phases = make(map[string]PhaseStats)
+	}
+	stats := s.phases[phase]
+	stats.Items += int64(items)
+	stats.LastItems = items
+	s.phases[phase] = stats
+}
+
+// Status returns a snapshot of the sync system: last success/attempt, failures, backoff,
+// bytes and entries sent per phase, and sync durations.
+func (c *Client) Status() Status {
+	c.mu.Lock()
+	running := c.running
+	backoff := max(time.Until(c.retryAfter), 0)
+	c.mu.Unlock()
+
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	status := Status{
+		Running:             running,
+		LastAttempt:         c.stats.lastAttempt,
+		LastSuccess:         c.stats.lastSuccess,
+		LastError:           c.stats.lastError,
+		LastFailureClass:    c.stats.lastFailureClass,
+		ConsecutiveFailures: c.stats.consecutiveFailures,
+		CurrentBackoff:      backoff,
+		Syncs:               c.stats.syncs,
+		Failures:            c.stats.failures,
+		BytesSent:           c.stats.bytesSent,
+		Phases:              make(map[string]PhaseStats, len(c.stats.phases)),
+		LastDuration:        c.stats.lastDuration,
+		MaxDuration:         c.stats.maxDuration,
+	}
+	for phase, stats := range c.stats.phases {
+		status.Phases[phase] = stats
+	}
+	if c.stats.syncs > 0 {
+		status.AverageDuration = c.stats.totalDuration / time.Duration(c.stats.syncs)
+	}
+	return status
+}
+
+// data converts Status to component data (durations in milliseconds).
+func (s Status) data() map[string]interface{} {
+	phases := make(map[string]interface{}, len(s.Phases))
+	for phase, stats := range s.Phases {
+		phases[phase] = map[string]interface{}{
+			"requests":   stats.Requests,
+			"bytes":      stats.Bytes,
+			"items":      stats.Items,
+			"last_items": stats.LastItems,
+		}
+	}
+
+	return map[string]interface{}{
+		"running":              s.Running,
+		"last_attempt":         formatStatusTime(s.LastAttempt),
+		"last_success":         formatStatusTime(s.LastSuccess),
+		"last_error":           s.LastError,
+		"last_failure_class":   string(s.LastFailureClass),
+		"consecutive_failures": s.ConsecutiveFailures,
+		"current_backoff_ms":   s.CurrentBackoff.Milliseconds(),
+		"syncs":                s.Syncs,
+		"failures":             s.Failures,
+		"bytes_sent":           s.BytesSent,
+		"phases":               phases,
+		"last_duration_ms":     s.LastDuration.Milliseconds(),
+		"average_duration_ms":  s.AverageDuration.Milliseconds(),
+		"max_duration_ms":      s.MaxDuration.Milliseconds(),
+	}
+}
+
+// formatStatusTime formats like the heartbeat (RFC3339 without nanoseconds, nil = never).
+func formatStatusTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02T15:04:05+00:00")
+}
+
+// clientComponentData provides the introspection-client component: library, protocol and
+// sync status. The snapshot is kept for one interval (counters change on every sync and would
+// otherwise resend the component every time), but refreshed at once when health changes.
+func (c *Client) clientComponentData() interface{} {
+	status := c.Status()
+	healthy := status.ConsecutiveFailures == 0
+	capabilities := c.ServerCapabilities()
+
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	// 1s tolerance: the registry collects exactly when the interval has passed
+	maxAge := time.Duration(statusInterval.Seconds())*time.Second - time.Second
+	if c.stats.snapshot != nil && healthy == c.stats.snapshotHealthy && time.Since(c.stats.snapshotAt) < maxAge {
+		return c.stats.snapshot
+	}
+
+	serverData := map[string]interface{}{
+		"negotiated": capabilities != nil,
+	}
+	if capabilities != nil {
+		serverData["protocol_version"] = capabilities.ProtocolVersion
+		serverData["server_version"] = capabilities.ServerVersion
+		serverData["features"] = capabilities.Features
+		serverData["legacy"] = capabilities.Legacy
+	}
+
+	c.stats.snapshot = map[string]interface{}{
+		"library":          "introspection-client-go",
+		"library_version":  LibraryVersion,
+		"protocol_version": ProtocolVersion,
+		"features":         c.clientFeatures(),
+		"compression":      c.compressor.Encoding().String(),
+		"server":           serverData,
+		"sync":             status.data(),
+	}
+	c.stats.snapshotAt = time.Now()
+	c.stats.snapshotHealthy = healthy
+	return c.stats.snapshot
+}
@@ -0,0 +1,172 @@
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is a request Content-Encoding.
+type Encoding string
+
+const (
+	EncodingIdentity Encoding = ""     // Uncompressed (no Content-Encoding header)
+	EncodingGzip     Encoding = "gzip" // Supported by every HTTP server stack
+	EncodingZstd     Encoding = "zstd" // Better ratio and speed, not supported everywhere
+)
+
+// DefaultCompressionThreshold is the payload size below which requests are sent uncompressed.
+const DefaultCompressionThreshold = 1024
+
+// fallbackOrder lists encodings from most to least preferred.
+var fallbackOrder = []Encoding{EncodingZstd, EncodingGzip, EncodingIdentity}
+
+// Compressor compresses request bodies and negotiates the encoding with the server:
+// a 415 response downgrades to the next encoding (or one listed in the response's Accept-Encoding).
+type Compressor struct {
+	threshold int
+
+	mu        sync.Mutex
+	preferred Encoding // Configured encoding (upper bound for Negotiate)
+	encoding  Encoding // Current (possibly downgraded) encoding
+}
+
+// NewCompressor creates a compressor for the preferred encoding (threshold <= 0 = DefaultCompressionThreshold).
+func NewCompressor(preferred Encoding, threshold int) (*Compressor, error) {
+	if err := preferred.Validate(); err != nil {
+		return nil, err
+	}
+	if threshold <= 0 {
+		threshold = DefaultCompressionThreshold
+	}
+	return &Compressor{threshold: threshold, preferred: preferred, encoding: preferred}, nil
+}
+
+// Validate checks that the encoding is supported.
+func (e Encoding) Validate() error {
+	switch e {
+	case EncodingIdentity, EncodingGzip, EncodingZstd:
+		return nil
+	default:
+		return fmt.Errorf("unsupported encoding %q (must be gzip, zstd or empty)", e)
+	}
+}
+
+// String returns the encoding name ("identity" for uncompressed).
+func (e Encoding) String() string {
+	if e == EncodingIdentity {
+		return "identity"
+	}
+	return string(e)
+}
+
+// Encoding returns the currently negotiated encoding.
+func (c *Compressor) Encoding() Encoding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.encoding
+}
+
+// Encode compresses data with the current encoding. Payloads below the threshold stay uncompressed.
+func (c *Compressor) Encode(data []byte) ([]byte, Encoding, error) {
+	encoding := c.Encoding()
+	if encoding == EncodingIdentity || len(data) < c.threshold {
+		return data, EncodingIdentity, nil
+	}
+
+	var buf bytes.Buffer
+	switch encoding {
+	case EncodingGzip:
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, "", err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+	case EncodingZstd:
+		writer, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return nil, "", err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+	return buf.Bytes(), encoding, nil
+}
+
+// Rejected handles a 415 for a request sent with encoding. Returns true if the request
+// should be retried with the downgraded encoding (false if it was sent uncompressed).
+func (c *Compressor) Rejected(encoding Encoding, resp *http.Response) bool {
+	if encoding == EncodingIdentity {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// RFC 7694: the server may list the encodings it accepts
+	accepted := map[Encoding]bool{}
+	if header := resp.Header.Get("Accept-Encoding"); header != "" {
+		for _, value := range strings.Split(header, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(value), ";")
+			accepted[Encoding(strings.ToLower(name))] = true
+		}
+	}
+
+	// Next encoding below the rejected one (accepted by the server, if it told us)
+	next := EncodingIdentity
+	below := false
+	for _, candidate := range fallbackOrder {
+		if candidate == encoding {
+			below = true
+			continue
+		}
+		if below && candidate != EncodingIdentity && (len(accepted) == 0 || accepted[candidate]) {
+			next = candidate
+			break
+		}
+	}
+
+	// Only downgrade (a concurrent request may already have downgraded further)
+	if encodingRank(next) > encodingRank(c.encoding) {
+		c.encoding = next
+	}
+	return true
+}
+
+// Negotiate selects the most preferred encoding (not above the configured one) that the server
+// advertised. Unlike Rejected, this can upgrade again (e.g., after the server was updated).
+func (c *Compressor) Negotiate(accepted []Encoding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.encoding = EncodingIdentity
+	for _, candidate := range fallbackOrder[encodingRank(c.preferred):] {
+		for _, encoding := range accepted {
+			if candidate == encoding && candidate != EncodingIdentity {
+				c.encoding = candidate
+				return
+			}
+		}
+	}
+}
+
+// encodingRank returns the position in fallbackOrder (higher = less preferred).
+func encodingRank(encoding Encoding) int {
+	for i, candidate := range fallbackOrder {
+		if candidate == encoding {
+			return i
+		}
+	}
+	return len(fallbackOrder)
+}
@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCompressorRejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		preferred      Encoding
+		rejected       []Encoding // Encodings the server rejects with 415, in order
+		acceptEncoding string     // Accept-Encoding of the 415 response
+		wantRetry      bool       // Result of the last Rejected call
+		want           Encoding
+	}{
+		{name: "zstd to gzip", preferred: EncodingZstd, rejected: []Encoding{EncodingZstd}, wantRetry: true, want: EncodingGzip},
+		{name: "gzip to identity", preferred: EncodingGzip, rejected: []Encoding{EncodingGzip}, wantRetry: true, want: EncodingIdentity},
+		{name: "zstd then gzip", preferred: EncodingZstd, rejected: []Encoding{EncodingZstd, EncodingGzip}, wantRetry: true, want: EncodingIdentity},
+		{name: "identity not retried", preferred: EncodingIdentity, rejected: []Encoding{EncodingIdentity}, wantRetry: false, want: EncodingIdentity},
+		{name: "accept-encoding gzip", preferred: EncodingZstd, rejected: []Encoding{EncodingZstd}, acceptEncoding: "GZIP;q=1.0, identity", wantRetry: true, want: EncodingGzip},
+		{name: "accept-encoding identity only", preferred: EncodingZstd, rejected: []Encoding{EncodingZstd}, acceptEncoding: "identity", wantRetry: true, want: EncodingIdentity},
+		{name: "stale rejection does not upgrade", preferred: EncodingZstd, rejected: []Encoding{EncodingZstd, EncodingGzip, EncodingZstd}, wantRetry: true, want: EncodingIdentity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressor, err := NewCompressor(tt.preferred, 0)
+			if err != nil {
+				t.Fatalf("NewCompressor: %v", err)
+			}
+
+			resp := &http.Response{StatusCode: http.StatusUnsupportedMediaType, Header: http.Header{}}
+			if tt.acceptEncoding != "" {
+				resp.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			var retry bool
+			for _, encoding := range tt.rejected {
+				retry = compressor.Rejected(encoding, resp)
+			}
+			if retry != tt.wantRetry {
+				t.Errorf("Rejected = %v, want %v", retry, tt.wantRetry)
+			}
+			if got := compressor.Encoding(); got != tt.want {
+				t.Errorf("Encoding = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressorNegotiate(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred Encoding
+		accepted  []Encoding
+		want      Encoding
+	}{
+		{name: "both advertised", preferred: EncodingZstd, accepted: []Encoding{EncodingGzip, EncodingZstd}, want: EncodingZstd},
+		{name: "capped at preferred", preferred: EncodingGzip, accepted: []Encoding{EncodingZstd, EncodingGzip}, want: EncodingGzip},
+		{name: "only gzip advertised", preferred: EncodingZstd, accepted: []Encoding{EncodingGzip}, want: EncodingGzip},
+		{name: "preferred not advertised", preferred: EncodingGzip, accepted: []Encoding{EncodingZstd}, want: EncodingIdentity},
+		{name: "nothing advertised", preferred: EncodingZstd, accepted: nil, want: EncodingIdentity},
+		{name: "identity preferred", preferred: EncodingIdentity, accepted: []Encoding{EncodingZstd, EncodingGzip}, want: EncodingIdentity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressor, err := NewCompressor(tt.preferred, 0)
+			if err != nil {
+				t.Fatalf("NewCompressor: %v", err)
+			}
+			compressor.Negotiate(tt.accepted)
+			if got := compressor.Encoding(); got != tt.want {
+				t.Errorf("Encoding = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressorNegotiateUpgradesAfterRejected(t *testing.T) {
+	compressor, err := NewCompressor(EncodingZstd, 0)
+	if err != nil {
+		t.Fatalf("NewCompressor: %v", err)
+	}
+
+	resp := &http.Response{StatusCode: http.StatusUnsupportedMediaType, Header: http.Header{}}
+	compressor.Rejected(EncodingZstd, resp)
+	compressor.Rejected(EncodingGzip, resp)
+	if got := compressor.Encoding(); got != EncodingIdentity {
+		t.Fatalf("Encoding after rejections = %s, want identity", got)
+	}
+
+	// Server was updated and advertises zstd again
+	compressor.Negotiate([]Encoding{EncodingZstd, EncodingGzip})
+	if got := compressor.Encoding(); got != EncodingZstd {
+		t.Errorf("Encoding after Negotiate = %s, want zstd", got)
+	}
+}
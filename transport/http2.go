@@ -3,18 +3,45 @@
 package transport
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 
 	"golang.org/x/net/http2"
 )
 
+// TLSObserver receives the certificates actually used on the wire (e.g., standard.CertificateMonitor).
+type TLSObserver interface {
+	// ClientCertificateLoaded is called once with the client certificate chain loaded from certPath.
+	ClientCertificateLoaded(certPath string, chain []*x509.Certificate)
+	// PeerCertificatesObserved is called after every verified TLS handshake with the server's chain.
+	PeerCertificatesObserved(serverName string, chain []*x509.Certificate)
+}
+
 // BuildHTTP2Client creates an HTTP/2 client with mTLS 1.3.
 // Note: Previously BuildHTTP3Client - downgraded due to kernel UDP buffer limits.
 func BuildHTTP2Client(certPath, keyPath, caPath string) (*http.Client, error) {
+	return BuildHTTP2ClientWithOptions(certPath, keyPath, caPath, ClientOptions{})
+}
+
+// ClientOptions configures BuildHTTP2ClientWithOptions (zero value = defaults, no observation).
+type ClientOptions struct {
+	Observer TLSObserver // Receives the loaded client certificate and every verified server chain (nil = no observation)
+	Timeouts Timeouts    // Dial, TLS handshake and response header timeouts (zero fields = defaults)
+}
+
+// BuildHTTP2ClientWithOptions creates an HTTP/2 client with mTLS 1.3, timeouts and optional observation.
+func BuildHTTP2ClientWithOptions(certPath, keyPath, caPath string, options ClientOptions) (*http.Client, error) {
+	observer := options.Observer
+	if err := options.Timeouts.Validate(); err != nil {
+		return nil, err
+	}
+	timeouts := options.Timeouts.withDefaults()
+
 	if certPath == "" {
 		return nil, fmt.Errorf("certPath required")
 	}
@@ -58,14 +85,63 @@ func BuildHTTP2Client(certPath, keyPath, caPath string) (*http.Client, error) {
 		MaxVersion:   tls.VersionTLS13,
 	}
 
-	// HTTP/2 transport with mTLS
+	if observer != nil {
+		// Report what was actually loaded (may differ from the file on disk after rotation)
+		var chain []*x509.Certificate
+		for _, der := range clientCert.Certificate {
+			if cert, err := x509.ParseCertificate(der); err == nil {
+				chain = append(chain, cert)
+			}
+		}
+		observer.ClientCertificateLoaded(certPath, chain)
+
+		// Runs after standard verification passed (also on resumption) and never rejects
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			observer.PeerCertificatesObserved(observedServerName(state), state.PeerCertificates)
+			return nil
+		}
+	}
+
+	// HTTP/2 transport with mTLS (health checks close dead connections)
 	transport := &http2.Transport{
 		TLSClientConfig: tlsConfig,
+		ReadIdleTimeout: http2ReadIdleTimeout,
+		PingTimeout:     http2PingTimeout,
+	}
+
+	// Dial and handshake with separate timeouts (http2.Transport has no handshake timeout)
+	transport.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+		dialer := &net.Dialer{Timeout: timeouts.Dial}
+		rawConn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		conn := tls.Client(rawConn, cfg)
+		handshakeCtx, cancel := context.WithTimeout(ctx, timeouts.TLSHandshake)
+		defer cancel()
+		if err := conn.HandshakeContext(handshakeCtx); err != nil {
+			rawConn.Close()
+			return nil, err
+		}
+		return conn, nil
 	}
 
 	client := &http.Client{
-		Transport: transport,
+		Transport: &responseHeaderTimeout{next: transport, timeout: timeouts.ResponseHeader},
 	}
 
 	return client, nil
 }
+
+// observedServerName returns the SNI of a connection. IP address servers get no SNI: their
+// chain is keyed by the leaf's first IP SAN instead.
+func observedServerName(state tls.ConnectionState) string {
+	if state.ServerName != "" || len(state.PeerCertificates) == 0 {
+		return state.ServerName
+	}
+	if ips := state.PeerCertificates[0].IPAddresses; len(ips) > 0 {
+		return ips[0].String()
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
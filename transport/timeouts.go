@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Default timeouts (zero fields in Timeouts).
+const (
+	DefaultDialTimeout           = 5 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 20 * time.Second
+)
+
+// HTTP/2 connection health checks: ping after this much silence, close if unanswered.
+// Detects connections a hung server or middlebox left half-open (otherwise reused forever).
+const (
+	http2ReadIdleTimeout = 30 * time.Second
+	http2PingTimeout     = 15 * time.Second
+)
+
+// Timeouts bounds the stages of a request (zero = default). Overall request deadlines are set
+// by the caller via the request context.
+type Timeouts struct {
+	Dial           time.Duration // TCP connect
+	TLSHandshake   time.Duration // TLS handshake after connect
+	ResponseHeader time.Duration // Request sent until response headers received
+}
+
+// Validate checks that no timeout is negative.
+func (t Timeouts) Validate() error {
+	if t.Dial < 0 || t.TLSHandshake < 0 || t.ResponseHeader < 0 {
+		return fmt.Errorf("timeouts must be >= 0")
+	}
+	return nil
+}
+
+// withDefaults fills zero fields.
+func (t Timeouts) withDefaults() Timeouts {
+	if t.Dial == 0 {
+		t.Dial = DefaultDialTimeout
+	}
+	if t.TLSHandshake == 0 {
+		t.TLSHandshake = DefaultTLSHandshakeTimeout
+	}
+	if t.ResponseHeader == 0 {
+		t.ResponseHeader = DefaultResponseHeaderTimeout
+	}
+	return t
+}
+
+// responseHeaderTimeout cancels a request whose response headers do not arrive in time after the
+// request was written (dial and TLS handshake have their own timeouts). Reading the body is not
+// limited (it is bounded by the caller's context).
+type responseHeaderTimeout struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *responseHeaderTimeout) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	// Start the timer once the request is on the wire (trace hooks run on transport goroutines)
+	var mu sync.Mutex
+	var timer *time.Timer
+	returned := false
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			if timer == nil && !returned {
+				timer = time.AfterFunc(t.timeout, cancel)
+			}
+		},
+	}
+
+	resp, err := t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+	mu.Lock()
+	returned = true
+	expired := timer != nil && !timer.Stop()
+	mu.Unlock()
+	if err != nil {
+		cancel()
+		if expired {
+			return nil, fmt.Errorf("timeout awaiting response headers after %s: %w", t.timeout, err)
+		}
+		return nil, err
+	}
+
+	// Release the context with the body
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels the request context when the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the request context.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}